@@ -0,0 +1,321 @@
+package backtest
+
+import (
+	"encoding/csv"
+	"github.com/AlexanderKolesnkov/golang-utils-stuff/consts"
+	"github.com/AlexanderKolesnkov/golang-utils-stuff/himath"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// hourly builds hourly candles from open, high, low, close
+func hourly(ohlc ...[4]float64) []Candle {
+	candles := make([]Candle, len(ohlc))
+	for i, v := range ohlc {
+		candles[i] = Candle{Time: start.Add(time.Duration(i) * time.Hour), Open: v[0], High: v[1], Low: v[2], Close: v[3], Volume: 1}
+	}
+	return candles
+}
+
+// script submits the orders returned for the candle index
+func script(t *testing.T, orders map[int][]Order) Strategy {
+	i := 0
+	return StrategyFunc(func(candle Candle, broker *Broker) {
+		for _, order := range orders[i] {
+			_, err := broker.Submit(order)
+			assert.NoError(t, err)
+		}
+		i++
+	})
+}
+
+func TestMarketOrder(t *testing.T) {
+	config := Config{InitialBalance: 1000, TakerFee: 0.001, Slippage: 0.01, Timeframe: "60"}
+	candles := hourly([4]float64{100, 100, 100, 100}, [4]float64{110, 112, 108, 110}, [4]float64{120, 121, 119, 120})
+	result, err := Run(config, candles, script(t, map[int][]Order{0: {{Side: consts.Buy, Type: Market, Qty: 1}}}))
+	assert.NoError(t, err)
+
+	assert.Len(t, result.Fills, 1)
+	fill := result.Fills[0]
+	assert.Equal(t, candles[1].Time, fill.Time, "market order fills at the next open")
+	assert.InDelta(t, 111.1, fill.Price, 1e-9)
+	assert.InDelta(t, 0.1111, fill.Fee, 1e-9)
+
+	assert.Len(t, result.Equity, 3)
+	assert.Equal(t, 1000.0, result.Equity[0].Equity)
+	assert.InDelta(t, 1000-0.1111-1.1, result.Equity[1].Equity, 1e-9)
+	assert.InDelta(t, 1000-0.1111+8.9, result.Equity[2].Equity, 1e-9)
+	assert.Equal(t, 1.0, result.Equity[2].Position)
+	assert.NotNil(t, result.Position)
+	assert.Equal(t, Filled, result.Orders[0].Status)
+}
+
+func TestLimitAndStopOrders(t *testing.T) {
+	tests := []struct {
+		name   string
+		order  Order
+		candle [4]float64
+		price  float64
+		filled bool
+	}{
+		{name: "buy limit touched", order: Order{Side: consts.Buy, Type: Limit, Qty: 1, Price: 95}, candle: [4]float64{100, 101, 94, 96}, price: 95, filled: true},
+		{name: "buy limit gap fills at open", order: Order{Side: consts.Buy, Type: Limit, Qty: 1, Price: 95}, candle: [4]float64{90, 91, 89, 90}, price: 90, filled: true},
+		{name: "buy limit not reached", order: Order{Side: consts.Buy, Type: Limit, Qty: 1, Price: 95}, candle: [4]float64{100, 101, 96, 97}},
+		{name: "sell limit touched", order: Order{Side: consts.Sell, Type: Limit, Qty: 1, Price: 105}, candle: [4]float64{100, 106, 99, 101}, price: 105, filled: true},
+		{name: "buy stop triggered", order: Order{Side: consts.Buy, Type: Stop, Qty: 1, Price: 105}, candle: [4]float64{100, 106, 99, 101}, price: 105, filled: true},
+		{name: "buy stop gap fills at open", order: Order{Side: consts.Buy, Type: Stop, Qty: 1, Price: 105}, candle: [4]float64{110, 111, 109, 110}, price: 110, filled: true},
+		{name: "sell stop not reached", order: Order{Side: consts.Sell, Type: Stop, Qty: 1, Price: 95}, candle: [4]float64{100, 101, 96, 97}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			candles := hourly([4]float64{100, 100, 100, 100}, tt.candle)
+			result, err := Run(Config{InitialBalance: 1000, Timeframe: "60"}, candles, script(t, map[int][]Order{0: {tt.order}}))
+			assert.NoError(t, err)
+
+			if !tt.filled {
+				assert.Empty(t, result.Fills)
+				assert.Equal(t, Pending, result.Orders[0].Status)
+				return
+			}
+			assert.Len(t, result.Fills, 1)
+			assert.Equal(t, tt.price, result.Fills[0].Price)
+		})
+	}
+}
+
+func TestFeesBySide(t *testing.T) {
+	config := Config{InitialBalance: 1000, MakerFee: -0.0001, TakerFee: 0.001, Slippage: 0.5, Timeframe: "60"}
+	candles := hourly([4]float64{100, 100, 100, 100}, [4]float64{100, 101, 99, 100})
+	result, err := Run(config, candles, script(t, map[int][]Order{0: {{Side: consts.Buy, Type: Limit, Qty: 1, Price: 100}}}))
+	assert.NoError(t, err)
+
+	assert.Len(t, result.Fills, 1)
+	assert.Equal(t, 100.0, result.Fills[0].Price, "limit orders have no slippage")
+	assert.InDelta(t, -0.01, result.Fills[0].Fee, 1e-12, "maker rebate")
+	assert.InDelta(t, 1000.01, result.Equity[1].Cash, 1e-9)
+}
+
+func TestStopLoss(t *testing.T) {
+	candles := hourly(
+		[4]float64{100, 100, 100, 100},
+		[4]float64{100, 102, 99, 101},
+		[4]float64{97, 98, 90, 92},
+	)
+	strategy := StrategyFunc(func(candle Candle, broker *Broker) {
+		if candle.Time.Equal(start) {
+			_, _ = broker.MarketOrder(consts.Buy, 2)
+			_, _ = broker.StopOrder(consts.Sell, 2, 95)
+		}
+	})
+	result, err := Run(Config{InitialBalance: 1000, Timeframe: "60"}, candles, strategy)
+	assert.NoError(t, err)
+
+	assert.Len(t, result.Fills, 2)
+	stop := result.Fills[1]
+	assert.Equal(t, Stop, stop.Type)
+	assert.Equal(t, 95.0, stop.Price)
+	assert.Equal(t, 2.0, stop.Closed)
+	assert.Equal(t, -10.0, stop.Pnl)
+	assert.Nil(t, result.Position)
+	assert.Equal(t, 990.0, result.Equity[2].Equity)
+}
+
+func TestReversal(t *testing.T) {
+	candles := hourly([4]float64{100, 100, 100, 100}, [4]float64{100, 100, 100, 100}, [4]float64{110, 110, 110, 110})
+	result, err := Run(Config{InitialBalance: 1000, Timeframe: "60"}, candles, script(t, map[int][]Order{
+		0: {{Side: consts.Buy, Type: Market, Qty: 1}},
+		1: {{Side: consts.Sell, Type: Market, Qty: 3}},
+	}))
+	assert.NoError(t, err)
+
+	assert.Len(t, result.Fills, 2)
+	assert.Equal(t, 1.0, result.Fills[1].Closed)
+	assert.Equal(t, 10.0, result.Fills[1].Pnl)
+	assert.NotNil(t, result.Position)
+	assert.Equal(t, consts.Sell, result.Position.Side)
+	assert.Equal(t, 2.0, result.Position.Qty)
+	assert.Equal(t, -2.0, result.Equity[2].Position)
+}
+
+func TestRejectedOrders(t *testing.T) {
+	candles := hourly([4]float64{100, 100, 100, 100}, [4]float64{100, 100, 100, 100})
+	result, err := Run(Config{InitialBalance: 150, Leverage: 1, Timeframe: "60"}, candles, script(t, map[int][]Order{0: {
+		{Side: consts.Buy, Type: Market, Qty: 2},
+		{Side: consts.Sell, Type: Market, Qty: 1, ReduceOnly: true},
+	}}))
+	assert.NoError(t, err)
+
+	assert.Empty(t, result.Fills)
+	assert.Equal(t, Rejected, result.Orders[0].Status)
+	assert.ErrorIs(t, result.Orders[0].Err, ErrNoMargin)
+	assert.ErrorIs(t, result.Orders[1].Err, ErrNoPosition)
+
+	// the same order fits with leverage
+	result, err = Run(Config{InitialBalance: 150, Leverage: 2, Timeframe: "60"}, candles, script(t, map[int][]Order{0: {
+		{Side: consts.Buy, Type: Market, Qty: 2},
+	}}))
+	assert.NoError(t, err)
+	assert.Len(t, result.Fills, 1)
+}
+
+func TestLiquidation(t *testing.T) {
+	config := Config{InitialBalance: 1000, Leverage: 10, MaintenanceMarginRate: 0.005, Timeframe: "60"}
+	candles := hourly(
+		[4]float64{100, 100, 100, 100},
+		[4]float64{100, 100, 100, 100},
+		[4]float64{95, 96, 89, 90},
+		[4]float64{90, 91, 89, 90},
+	)
+	result, err := Run(config, candles, script(t, map[int][]Order{0: {{Side: consts.Buy, Type: Market, Qty: 1}}}))
+	assert.NoError(t, err)
+
+	assert.Len(t, result.Fills, 2)
+	liquidation := result.Fills[1]
+	assert.Equal(t, Liquidation, liquidation.Type)
+	assert.InDelta(t, 90.5, liquidation.Price, 1e-9, "entry - (IM - MM) / qty")
+	assert.InDelta(t, -9.5, liquidation.Pnl, 1e-9)
+	assert.Nil(t, result.Position)
+	assert.InDelta(t, 990.5, result.Equity[3].Equity, 1e-9)
+}
+
+func TestCancel(t *testing.T) {
+	candles := hourly([4]float64{100, 100, 100, 100}, [4]float64{100, 101, 99, 100}, [4]float64{90, 91, 89, 90})
+	strategy := StrategyFunc(func(candle Candle, broker *Broker) {
+		switch {
+		case candle.Time.Equal(start):
+			_, _ = broker.LimitOrder(consts.Buy, 1, 95)
+		case len(broker.PendingOrders()) == 1:
+			assert.True(t, broker.Cancel(broker.PendingOrders()[0].ID))
+			assert.False(t, broker.Cancel(42))
+		}
+	})
+	result, err := Run(Config{InitialBalance: 1000, Timeframe: "60"}, candles, strategy)
+	assert.NoError(t, err)
+
+	assert.Empty(t, result.Fills)
+	assert.Equal(t, Cancelled, result.Orders[0].Status)
+}
+
+func TestCloseOnFinish(t *testing.T) {
+	candles := hourly([4]float64{100, 100, 100, 100}, [4]float64{100, 100, 100, 100}, [4]float64{100, 110, 100, 110})
+	config := Config{InitialBalance: 1000, TakerFee: 0.001, CloseOnFinish: true, Timeframe: "60"}
+	result, err := Run(config, candles, script(t, map[int][]Order{0: {{Side: consts.Buy, Type: Market, Qty: 1}}}))
+	assert.NoError(t, err)
+
+	assert.Nil(t, result.Position)
+	assert.Len(t, result.Fills, 2)
+	assert.InDelta(t, 1000-0.1+10-0.11, result.Equity[2].Equity, 1e-9)
+	assert.Equal(t, result.Equity[2].Cash, result.Equity[2].Equity)
+}
+
+func TestRunValidation(t *testing.T) {
+	candles := hourly([4]float64{100, 100, 100, 100}, [4]float64{100, 100, 100, 100})
+	noop := StrategyFunc(func(Candle, *Broker) {})
+
+	_, err := Run(Config{InitialBalance: 1000}, nil, noop)
+	assert.ErrorIs(t, err, ErrNoCandles)
+	_, err = Run(Config{InitialBalance: 1000}, []Candle{candles[1], candles[0]}, noop)
+	assert.ErrorIs(t, err, ErrUnsortedCandles)
+	_, err = Run(Config{}, candles, noop)
+	assert.ErrorIs(t, err, ErrInvalidBalance)
+	_, err = Run(Config{InitialBalance: 1000, Leverage: 0.5}, candles, noop)
+	assert.ErrorIs(t, err, himath.ErrInvalidLeverage)
+	_, err = Run(Config{InitialBalance: 1000, Slippage: -1}, candles, noop)
+	assert.ErrorIs(t, err, himath.ErrInvalidParameter)
+	_, err = Run(Config{InitialBalance: 1000, Timeframe: "2h"}, candles, noop)
+	assert.ErrorIs(t, err, himath.ErrUnknownTimeframe)
+
+	result, err := Run(Config{InitialBalance: 1000}, candles, noop)
+	assert.NoError(t, err)
+	summary, err := result.Summary()
+	assert.NoError(t, err, "empty timeframe only skips annualized metrics")
+	assert.True(t, math.IsNaN(summary.SharpeRatio))
+	assert.Equal(t, 1000.0, summary.FinalEquity)
+
+	b := newBroker(Config{InitialBalance: 1000, Leverage: 1})
+	_, err = b.Submit(Order{Side: "buy", Qty: 1})
+	assert.ErrorIs(t, err, himath.ErrInvalidSide)
+	_, err = b.MarketOrder(consts.Buy, 0)
+	assert.ErrorIs(t, err, himath.ErrInvalidQty)
+	_, err = b.LimitOrder(consts.Buy, 1, 0)
+	assert.ErrorIs(t, err, ErrInvalidPrice)
+	_, err = b.Submit(Order{Side: consts.Buy, Type: Liquidation, Qty: 1})
+	assert.ErrorIs(t, err, ErrInvalidType)
+	_, err = b.ClosePosition()
+	assert.ErrorIs(t, err, ErrNoPosition)
+}
+
+// crossover buys after a green candle and closes the long after a red one
+func crossover(candle Candle, broker *Broker) {
+	position, open := broker.Position()
+	switch {
+	case !open && candle.Close > candle.Open:
+		_, _ = broker.MarketOrder(consts.Buy, 1)
+	case open && position.Side == consts.Buy && candle.Close < candle.Open:
+		_, _ = broker.ClosePosition()
+	}
+}
+
+func TestDeterministic(t *testing.T) {
+	var ohlc [][4]float64
+	price := 100.0
+	for i := 0; i < 200; i++ {
+		next := price * (1 + 0.01*float64(i%7-3))
+		ohlc = append(ohlc, [4]float64{price, price*1.02 + 1, next*0.98 - 1, next})
+		price = next
+	}
+	candles := hourly(ohlc...)
+	config := Config{InitialBalance: 1000, Leverage: 3, TakerFee: 0.00055, Slippage: 0.0005, Timeframe: "60"}
+
+	first, err := Run(config, candles, StrategyFunc(crossover))
+	assert.NoError(t, err)
+	second, err := Run(config, candles, StrategyFunc(crossover))
+	assert.NoError(t, err)
+	assert.Equal(t, first, second)
+
+	summary, err := first.Summary()
+	assert.NoError(t, err)
+	assert.Equal(t, len(first.TradePnls()), summary.Trades)
+	assert.Positive(t, summary.Trades)
+	assert.InDelta(t, first.Equity[len(first.Equity)-1].Equity, summary.FinalEquity, 1e-9)
+	assert.InDelta(t, summary.FinalEquity/1000-1, summary.TotalReturn, 1e-12)
+	assert.Positive(t, summary.Fees)
+}
+
+func TestWriteCsv(t *testing.T) {
+	candles := hourly([4]float64{100, 100, 100, 100}, [4]float64{100, 100, 100, 100}, [4]float64{110, 110, 110, 110})
+	result, err := Run(Config{InitialBalance: 1000, Timeframe: "60"}, candles, script(t, map[int][]Order{
+		0: {{Side: consts.Buy, Type: Market, Qty: 1}},
+		1: {{Side: consts.Sell, Type: Market, Qty: 1}},
+	}))
+	assert.NoError(t, err)
+
+	dir := t.TempDir()
+	assert.NoError(t, result.WriteFillsCsv(filepath.Join(dir, "fills")))
+	assert.NoError(t, result.WriteEquityCsv(filepath.Join(dir, "equity")))
+
+	fills := readCsv(t, filepath.Join(dir, "fills.csv"))
+	assert.Equal(t, FillsHeader(), fills[0])
+	assert.Equal(t, []string{"2024-01-01 02:00:00", "2", "Sell", "Market", "1", "110", "0", "10"}, fills[2])
+
+	equity := readCsv(t, filepath.Join(dir, "equity.csv"))
+	assert.Equal(t, EquityHeader(), equity[0])
+	assert.Len(t, equity, len(candles)+1)
+	assert.Equal(t, []string{"2024-01-01 01:00:00", "1000", "1000", "1"}, equity[2])
+}
+
+func readCsv(t *testing.T, path string) [][]string {
+	file, err := os.Open(path)
+	assert.NoError(t, err)
+	defer file.Close()
+
+	records, err := csv.NewReader(file).ReadAll()
+	assert.NoError(t, err)
+	return records
+}
@@ -0,0 +1,292 @@
+package backtest
+
+import (
+	"github.com/AlexanderKolesnkov/golang-utils-stuff/consts"
+	"github.com/AlexanderKolesnkov/golang-utils-stuff/himath"
+	"math"
+	"time"
+)
+
+// Candle OHLCV candle, Time is the open time
+type Candle struct {
+	Time   time.Time
+	Open   float64
+	High   float64
+	Low    float64
+	Close  float64
+	Volume float64
+}
+
+// Config of the simulated account, fees and slippage are fractions (0.00055 is 0.055%)
+type Config struct {
+	InitialBalance float64
+	// Leverage of every position, 0 means 1
+	Leverage float64
+	// MakerFee paid by Limit orders, TakerFee by Market, Stop and liquidation fills
+	MakerFee float64
+	TakerFee float64
+	// Slippage moves Market and Stop fills against the order: price * (1 ± slippage)
+	Slippage float64
+	// MaintenanceMarginRate of the position value, 0 liquidates only at the bankruptcy price
+	MaintenanceMarginRate float64
+	// Timeframe code of the candles ("1", "5", ..., "D"), used to annualize the summary.
+	// Run rejects unknown codes before simulating, empty Timeframe leaves SharpeRatio of the summary NaN.
+	Timeframe string
+	// CloseOnFinish closes the open position at the close of the last candle
+	CloseOnFinish bool
+}
+
+// Strategy receives every candle after it closes, orders submitted to the broker
+// are executed from the next candle
+type Strategy interface {
+	OnCandle(candle Candle, broker *Broker)
+}
+
+// StrategyFunc adapts a function to Strategy
+type StrategyFunc func(candle Candle, broker *Broker)
+
+// OnCandle calls f
+func (f StrategyFunc) OnCandle(candle Candle, broker *Broker) {
+	f(candle, broker)
+}
+
+// Broker simulated exchange account of one instrument in one-way mode: orders of the opposite side
+// reduce the position first and open the opposite one with the rest
+type Broker struct {
+	config   Config
+	cash     float64
+	position *himath.Position
+	orders   []*Order
+	pending  []*Order
+	fills    []Fill
+	candle   Candle
+}
+
+func newBroker(config Config) *Broker {
+	return &Broker{config: config, cash: config.InitialBalance}
+}
+
+// Submit queues the order for execution from the next candle and returns its ID
+func (b *Broker) Submit(order Order) (int, error) {
+	if err := order.validate(); err != nil {
+		return 0, err
+	}
+
+	order.ID = len(b.orders) + 1
+	order.Status = Pending
+	order.Placed = b.candle.Time
+	order.Err = nil
+	b.orders = append(b.orders, &order)
+	b.pending = append(b.pending, &order)
+	return order.ID, nil
+}
+
+// MarketOrder submits Market order
+func (b *Broker) MarketOrder(side string, qty float64) (int, error) {
+	return b.Submit(Order{Side: side, Type: Market, Qty: qty})
+}
+
+// LimitOrder submits Limit order
+func (b *Broker) LimitOrder(side string, qty, price float64) (int, error) {
+	return b.Submit(Order{Side: side, Type: Limit, Qty: qty, Price: price})
+}
+
+// StopOrder submits reduce only Stop order, e.g. stop-loss of the position
+func (b *Broker) StopOrder(side string, qty, price float64) (int, error) {
+	return b.Submit(Order{Side: side, Type: Stop, Qty: qty, Price: price, ReduceOnly: true})
+}
+
+// ClosePosition submits reduce only Market order for the whole position
+func (b *Broker) ClosePosition() (int, error) {
+	if b.position == nil {
+		return 0, ErrNoPosition
+	}
+	return b.Submit(Order{Side: opposite(b.position.Side), Type: Market, Qty: b.position.Qty, ReduceOnly: true})
+}
+
+// Cancel cancels the pending order, false when it is not pending
+func (b *Broker) Cancel(id int) bool {
+	for i, order := range b.pending {
+		if order.ID == id {
+			order.Status = Cancelled
+			b.pending = append(b.pending[:i], b.pending[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// CancelAll cancels all pending orders
+func (b *Broker) CancelAll() {
+	for _, order := range b.pending {
+		order.Status = Cancelled
+	}
+	b.pending = b.pending[:0]
+}
+
+// Position returns copy of the open position, false when there is none
+func (b *Broker) Position() (himath.Position, bool) {
+	if b.position == nil {
+		return himath.Position{}, false
+	}
+	return *b.position, true
+}
+
+// PendingOrders returns copies of orders waiting for execution
+func (b *Broker) PendingOrders() []Order {
+	orders := make([]Order, len(b.pending))
+	for i, order := range b.pending {
+		orders[i] = *order
+	}
+	return orders
+}
+
+// Cash returns wallet balance: initial balance plus realised pnl minus fees
+func (b *Broker) Cash() float64 {
+	return b.cash
+}
+
+// Equity returns cash plus unrealised pnl at the close of the last candle
+func (b *Broker) Equity() float64 {
+	return b.equity(b.candle.Close)
+}
+
+func (b *Broker) equity(price float64) float64 {
+	if b.position == nil {
+		return b.cash
+	}
+	return b.cash + b.position.UnrealisedPnl(price)
+}
+
+// execute fills pending orders triggered by the candle in submission order, then checks liquidation
+func (b *Broker) execute(candle Candle) {
+	pending := b.pending
+	b.pending = make([]*Order, 0, len(pending))
+	for _, order := range pending {
+		price, ok := order.match(candle)
+		if !ok {
+			b.pending = append(b.pending, order)
+			continue
+		}
+		b.fill(order, price, candle.Time)
+	}
+	b.liquidate(candle)
+}
+
+// fill executes the order at price, rejecting it when there is nothing to reduce or not enough margin
+func (b *Broker) fill(order *Order, price float64, t time.Time) {
+	rate := b.config.TakerFee
+	if order.Type == Limit {
+		rate = b.config.MakerFee
+	} else {
+		price = slip(price, order.Side, b.config.Slippage)
+	}
+
+	closeQty := 0.0
+	if b.position != nil && b.position.Side != order.Side {
+		closeQty = math.Min(order.Qty, b.position.Qty)
+	}
+	openQty := order.Qty - closeQty
+	if order.ReduceOnly {
+		openQty = 0
+	}
+
+	switch {
+	case closeQty == 0 && openQty == 0:
+		order.Status, order.Err = Rejected, ErrNoPosition
+		return
+	case openQty > 0 && b.available(price, closeQty, rate) < openQty*price*(1/b.config.Leverage+rate):
+		order.Status, order.Err = Rejected, ErrNoMargin
+		return
+	}
+
+	f := Fill{Time: t, OrderID: order.ID, Side: order.Side, Type: order.Type, Qty: closeQty + openQty, Price: price}
+	if closeQty > 0 {
+		f.Pnl, f.Closed = b.reduce(closeQty, price, rate)
+		f.Fee += closeQty * price * rate
+	}
+	if openQty > 0 {
+		b.open(order.Side, openQty, price, rate)
+		f.Fee += openQty * price * rate
+	}
+
+	order.Status = Filled
+	b.fills = append(b.fills, f)
+}
+
+// available returns equity left for new margin after closing closeQty of the position at price
+func (b *Broker) available(price, closeQty, rate float64) float64 {
+	if b.position == nil {
+		return b.cash
+	}
+	if closeQty >= b.position.Qty {
+		return b.equity(price) - closeQty*price*rate
+	}
+	return b.equity(price) - b.position.InitialMargin()
+}
+
+// open opens the position or adds to it and charges the fee
+func (b *Broker) open(side string, qty, price, rate float64) {
+	b.cash -= qty * price * rate
+	if b.position != nil {
+		b.position.FeeRate = rate
+		_ = b.position.Add(qty, price) // qty and price are validated
+		return
+	}
+
+	// side, qty, price and leverage are validated
+	b.position, _ = himath.NewPosition(side, qty, price, b.config.Leverage, rate)
+	if b.config.MaintenanceMarginRate > 0 {
+		b.position.Tiers = []himath.RiskTier{{Limit: math.Inf(1), MaintenanceMarginRate: b.config.MaintenanceMarginRate}}
+	}
+}
+
+// reduce closes qty of the position at price and returns pnl after the fee with the closed qty
+func (b *Broker) reduce(qty, price, rate float64) (float64, float64) {
+	b.position.FeeRate = rate
+	pnl, _ := b.position.Close(qty, price) // qty is at most the position qty
+	b.cash += pnl
+	if b.position.Qty == 0 {
+		b.position = nil
+	}
+	return pnl, qty
+}
+
+// liquidate closes the position at the liquidation price, or at the open when the candle gaps through it
+func (b *Broker) liquidate(candle Candle) {
+	if b.position == nil {
+		return
+	}
+
+	liquidation := b.position.LiquidationPrice()
+	side := opposite(b.position.Side)
+	var price float64
+	switch {
+	case side == consts.Sell && candle.Low <= liquidation && liquidation > 0:
+		price = math.Min(candle.Open, liquidation)
+	case side == consts.Buy && candle.High >= liquidation:
+		price = math.Max(candle.Open, liquidation)
+	default:
+		return
+	}
+
+	qty := b.position.Qty
+	f := Fill{Time: candle.Time, Side: side, Type: Liquidation, Qty: qty, Price: price, Fee: qty * price * b.config.TakerFee}
+	f.Pnl, f.Closed = b.reduce(qty, price, b.config.TakerFee)
+	b.fills = append(b.fills, f)
+}
+
+func opposite(side string) string {
+	if side == consts.Buy {
+		return consts.Sell
+	}
+	return consts.Buy
+}
+
+// slip moves price against the side
+func slip(price float64, side string, slippage float64) float64 {
+	if side == consts.Buy {
+		return price * (1 + slippage)
+	}
+	return price * (1 - slippage)
+}
@@ -0,0 +1,107 @@
+package backtest
+
+import (
+	"github.com/AlexanderKolesnkov/golang-utils-stuff/consts"
+	"github.com/AlexanderKolesnkov/golang-utils-stuff/himath"
+	"math"
+	"time"
+)
+
+// EquityPoint state of the account at the close of a candle
+type EquityPoint struct {
+	Time   time.Time // open time of the candle
+	Cash   float64
+	Equity float64
+	// Position signed qty: positive for long, negative for short
+	Position float64
+}
+
+// Result of the run
+type Result struct {
+	Config Config
+	Fills  []Fill
+	Equity []EquityPoint
+	Orders []Order
+	// Position left open after the last candle, nil when flat
+	Position *himath.Position
+}
+
+// Run replays candles sorted by time through the strategy. Every candle first executes pending orders
+// against its OHLC and checks liquidation, then the account is marked at the close and the strategy is called.
+// Within a candle orders are executed in submission order before the liquidation check.
+func Run(config Config, candles []Candle, strategy Strategy) (*Result, error) {
+	if config.Leverage == 0 {
+		config.Leverage = 1
+	}
+	if err := validateConfig(config); err != nil {
+		return nil, err
+	}
+	if len(candles) == 0 {
+		return nil, ErrNoCandles
+	}
+	for i := 1; i < len(candles); i++ {
+		if !candles[i].Time.After(candles[i-1].Time) {
+			return nil, ErrUnsortedCandles
+		}
+	}
+
+	b := newBroker(config)
+	equity := make([]EquityPoint, 0, len(candles))
+	for _, candle := range candles {
+		b.execute(candle)
+		b.candle = candle
+		equity = append(equity, b.mark())
+		strategy.OnCandle(candle, b)
+	}
+
+	if config.CloseOnFinish && b.position != nil {
+		last := candles[len(candles)-1]
+		side := opposite(b.position.Side)
+		qty, price := b.position.Qty, slip(last.Close, side, config.Slippage)
+		f := Fill{Time: last.Time, Side: side, Type: Market, Qty: qty, Price: price, Fee: qty * price * config.TakerFee}
+		f.Pnl, f.Closed = b.reduce(qty, price, config.TakerFee)
+		b.fills = append(b.fills, f)
+		equity[len(equity)-1] = b.mark()
+	}
+
+	result := &Result{Config: config, Fills: b.fills, Equity: equity, Orders: make([]Order, len(b.orders))}
+	for i, order := range b.orders {
+		result.Orders[i] = *order
+	}
+	if b.position != nil {
+		position := *b.position
+		result.Position = &position
+	}
+	return result, nil
+}
+
+func validateConfig(config Config) error {
+	switch {
+	case !(config.InitialBalance > 0) || math.IsInf(config.InitialBalance, 1):
+		return ErrInvalidBalance
+	case config.Leverage < 1 || math.IsInf(config.Leverage, 1):
+		return himath.ErrInvalidLeverage
+	case !(config.MakerFee > -1 && config.MakerFee < 1) || !(config.TakerFee >= 0 && config.TakerFee < 1):
+		return himath.ErrInvalidParameter
+	case !(config.Slippage >= 0 && config.Slippage < 1) || !(config.MaintenanceMarginRate >= 0 && config.MaintenanceMarginRate < 1):
+		return himath.ErrInvalidParameter
+	}
+	if config.Timeframe != "" {
+		if _, err := himath.PeriodsPerYear(config.Timeframe); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mark returns the account state at the close of the current candle
+func (b *Broker) mark() EquityPoint {
+	point := EquityPoint{Time: b.candle.Time, Cash: b.cash, Equity: b.Equity()}
+	if b.position != nil {
+		point.Position = b.position.Qty
+		if b.position.Side == consts.Sell {
+			point.Position = -point.Position
+		}
+	}
+	return point
+}
@@ -0,0 +1,18 @@
+// Package backtest replays OHLCV candles through a Strategy and simulates execution of its orders.
+//
+// The run is deterministic: the strategy sees a candle only after it closes, orders placed on that candle
+// are executed from the open of the next one in the order they were submitted, and no randomness is involved.
+// Positions, margin, fees and liquidation follow himath.Position of linear perpetual contracts.
+package backtest
+
+import "errors"
+
+var (
+	ErrNoCandles       = errors.New("no candles")
+	ErrUnsortedCandles = errors.New("candles are not sorted by time")
+	ErrInvalidBalance  = errors.New("initial balance must be positive")
+	ErrInvalidPrice    = errors.New("order price must be positive")
+	ErrInvalidType     = errors.New("unknown order type")
+	ErrNoPosition      = errors.New("no position to reduce")
+	ErrNoMargin        = errors.New("not enough margin")
+)
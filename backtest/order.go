@@ -0,0 +1,130 @@
+package backtest
+
+import (
+	"github.com/AlexanderKolesnkov/golang-utils-stuff/consts"
+	"github.com/AlexanderKolesnkov/golang-utils-stuff/himath"
+	"math"
+	"time"
+)
+
+// OrderType how the order is executed
+type OrderType int
+
+const (
+	// Market fills at the open of the next candle, pays taker fee and slippage
+	Market OrderType = iota
+	// Limit fills when the price trades at Price or better, pays maker fee
+	Limit
+	// Stop becomes a market order when the price trades through Price, pays taker fee and slippage
+	Stop
+	// Liquidation marks fills made by the engine when the price reaches the liquidation price
+	Liquidation
+)
+
+func (t OrderType) String() string {
+	switch t {
+	case Market:
+		return "Market"
+	case Limit:
+		return "Limit"
+	case Stop:
+		return "Stop"
+	case Liquidation:
+		return "Liquidation"
+	}
+	return "Unknown"
+}
+
+// OrderStatus state of the order
+type OrderStatus int
+
+const (
+	Pending OrderStatus = iota
+	Filled
+	Cancelled
+	// Rejected order did not pass the margin check or had nothing to reduce when it was triggered
+	Rejected
+)
+
+func (s OrderStatus) String() string {
+	switch s {
+	case Pending:
+		return "Pending"
+	case Filled:
+		return "Filled"
+	case Cancelled:
+		return "Cancelled"
+	case Rejected:
+		return "Rejected"
+	}
+	return "Unknown"
+}
+
+// Order request of the strategy, ID and Status are set by the broker
+type Order struct {
+	ID    int
+	Side  string // consts.Buy or consts.Sell
+	Type  OrderType
+	Qty   float64
+	Price float64 // limit price or stop trigger, ignored for Market
+	// ReduceOnly order only closes the position and never opens the opposite one
+	ReduceOnly bool
+
+	Status OrderStatus
+	Placed time.Time // open time of the candle the order was submitted on
+	Err    error     // reason of rejection
+}
+
+// validate checks the order fields that do not depend on the market
+func (o Order) validate() error {
+	if o.Side != consts.Buy && o.Side != consts.Sell {
+		return himath.ErrInvalidSide
+	}
+	if !(o.Qty > 0) || math.IsInf(o.Qty, 1) {
+		return himath.ErrInvalidQty
+	}
+	switch o.Type {
+	case Market:
+		return nil
+	case Limit, Stop:
+		if !(o.Price > 0) || math.IsInf(o.Price, 1) {
+			return ErrInvalidPrice
+		}
+		return nil
+	}
+	return ErrInvalidType
+}
+
+// match returns execution price of the order on the candle before slippage and whether it is executed.
+// Gaps are filled at the open when it is better than the limit price or worse than the stop price.
+func (o Order) match(candle Candle) (float64, bool) {
+	buy := o.Side == consts.Buy
+	switch {
+	case o.Type == Market:
+		return candle.Open, true
+	case o.Type == Limit && buy && candle.Low <= o.Price:
+		return math.Min(candle.Open, o.Price), true
+	case o.Type == Limit && !buy && candle.High >= o.Price:
+		return math.Max(candle.Open, o.Price), true
+	case o.Type == Stop && buy && candle.High >= o.Price:
+		return math.Max(candle.Open, o.Price), true
+	case o.Type == Stop && !buy && candle.Low <= o.Price:
+		return math.Min(candle.Open, o.Price), true
+	}
+	return 0, false
+}
+
+// Fill execution of an order, one fill per order
+type Fill struct {
+	Time    time.Time // open time of the candle the order was executed on
+	OrderID int       // 0 for liquidation
+	Side    string
+	Type    OrderType
+	Qty     float64
+	Price   float64 // execution price including slippage
+	Fee     float64
+	// Pnl realised pnl of the closed part after the closing fee, 0 for fills that only open or add
+	Pnl float64
+	// Closed qty of the position closed by the fill
+	Closed float64
+}
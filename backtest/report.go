@@ -0,0 +1,117 @@
+package backtest
+
+import (
+	"github.com/AlexanderKolesnkov/golang-utils-stuff/consts"
+	"github.com/AlexanderKolesnkov/golang-utils-stuff/himath"
+	"github.com/AlexanderKolesnkov/golang-utils-stuff/utils"
+	"math"
+	"strconv"
+)
+
+// Summary performance of the run, returns are fractions
+type Summary struct {
+	FinalEquity  float64
+	TotalReturn  float64
+	MaxDrawdown  float64
+	SharpeRatio  float64
+	Trades       int // fills that closed a position part
+	WinRate      float64
+	ProfitFactor float64
+	Fees         float64
+}
+
+// EquityCurve returns the initial balance followed by equity at the close of every candle
+func (r *Result) EquityCurve() []float64 {
+	curve := make([]float64, 0, len(r.Equity)+1)
+	curve = append(curve, r.Config.InitialBalance)
+	for _, point := range r.Equity {
+		curve = append(curve, point.Equity)
+	}
+	return curve
+}
+
+// TradePnls returns pnl after fees of every fill that closed a position part
+func (r *Result) TradePnls() []float64 {
+	var pnls []float64
+	for _, f := range r.Fills {
+		if f.Closed > 0 {
+			pnls = append(pnls, f.Pnl)
+		}
+	}
+	return pnls
+}
+
+// Summary returns metrics of the equity curve and the trades, Sharpe ratio is annualized by Config.Timeframe
+// and is NaN when Timeframe is empty
+func (r *Result) Summary() (Summary, error) {
+	curve := r.EquityCurve()
+	pnls := r.TradePnls()
+
+	sharpe := math.NaN()
+	if r.Config.Timeframe != "" {
+		var err error
+		if sharpe, err = himath.SharpeRatio(himath.PctChange(curve), 0, r.Config.Timeframe); err != nil {
+			return Summary{}, err
+		}
+	}
+
+	s := Summary{
+		FinalEquity:  curve[len(curve)-1],
+		TotalReturn:  himath.SimpleReturn(curve[0], curve[len(curve)-1]),
+		SharpeRatio:  sharpe,
+		Trades:       len(pnls),
+		WinRate:      himath.WinRate(pnls),
+		ProfitFactor: himath.ProfitFactor(pnls),
+	}
+	s.MaxDrawdown, _, _ = himath.MaxDrawdown(curve)
+	for _, f := range r.Fills {
+		s.Fees += f.Fee
+	}
+	return s, nil
+}
+
+// FillsHeader columns of WriteFillsCsv
+func FillsHeader() []string {
+	return []string{"Time", "OrderID", "Side", "Type", "Qty", "Price", "Fee", "Pnl"}
+}
+
+// EquityHeader columns of WriteEquityCsv
+func EquityHeader() []string {
+	return []string{"Time", "Cash", "Equity", "Position"}
+}
+
+// WriteFillsCsv writes the trade log into filepath.csv
+func (r *Result) WriteFillsCsv(filepath string) error {
+	records := make([][]string, len(r.Fills))
+	for i, f := range r.Fills {
+		records[i] = []string{
+			f.Time.Format(consts.TimeLayout),
+			strconv.Itoa(f.OrderID),
+			f.Side,
+			f.Type.String(),
+			formatFloat(f.Qty),
+			formatFloat(f.Price),
+			formatFloat(f.Fee),
+			formatFloat(f.Pnl),
+		}
+	}
+	return utils.WriteCsv(filepath, FillsHeader(), records)
+}
+
+// WriteEquityCsv writes the equity curve into filepath.csv
+func (r *Result) WriteEquityCsv(filepath string) error {
+	records := make([][]string, len(r.Equity))
+	for i, point := range r.Equity {
+		records[i] = []string{
+			point.Time.Format(consts.TimeLayout),
+			formatFloat(point.Cash),
+			formatFloat(point.Equity),
+			formatFloat(point.Position),
+		}
+	}
+	return utils.WriteCsv(filepath, EquityHeader(), records)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
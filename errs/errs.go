@@ -1,20 +1,323 @@
 package errs
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 	"net/http"
 )
 
+// RequestIDHeader header with the request ID, it is echoed in error responses
+const RequestIDHeader = "X-Request-ID"
+
+// RequestIDKey gin context key checked for the request ID before RequestIDHeader
+const RequestIDKey = "request_id"
+
+// Code machine readable error code
+type Code string
+
+const (
+	CodeBadRequest      Code = "bad_request"
+	CodeValidation      Code = "validation_failed"
+	CodeUnauthorized    Code = "unauthorized"
+	CodeForbidden       Code = "forbidden"
+	CodeNotFound        Code = "not_found"
+	CodeConflict        Code = "conflict"
+	CodeTooManyRequests Code = "too_many_requests"
+	CodeInternal        Code = "internal"
+	CodeUnavailable     Code = "unavailable"
+	CodeTimeout         Code = "timeout"
+)
+
+// statuses HTTP status of every code
+var statuses = map[Code]int{
+	CodeBadRequest:      http.StatusBadRequest,
+	CodeValidation:      http.StatusBadRequest,
+	CodeUnauthorized:    http.StatusUnauthorized,
+	CodeForbidden:       http.StatusForbidden,
+	CodeNotFound:        http.StatusNotFound,
+	CodeConflict:        http.StatusConflict,
+	CodeTooManyRequests: http.StatusTooManyRequests,
+	CodeInternal:        http.StatusInternalServerError,
+	CodeUnavailable:     http.StatusServiceUnavailable,
+	CodeTimeout:         http.StatusGatewayTimeout,
+}
+
+// Status returns HTTP status of the code, 500 for unknown codes
+func (c Code) Status() int {
+	if status, ok := statuses[c]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// Sentinel errors to match with errors.Is, any *Error with the same code matches them
+var (
+	ErrBadRequest      = &Error{Code: CodeBadRequest, Status: http.StatusBadRequest, Message: "bad request"}
+	ErrValidation      = &Error{Code: CodeValidation, Status: http.StatusBadRequest, Message: "validation failed"}
+	ErrUnauthorized    = &Error{Code: CodeUnauthorized, Status: http.StatusUnauthorized, Message: "unauthorized"}
+	ErrForbidden       = &Error{Code: CodeForbidden, Status: http.StatusForbidden, Message: "forbidden"}
+	ErrNotFound        = &Error{Code: CodeNotFound, Status: http.StatusNotFound, Message: "not found"}
+	ErrConflict        = &Error{Code: CodeConflict, Status: http.StatusConflict, Message: "conflict"}
+	ErrTooManyRequests = &Error{Code: CodeTooManyRequests, Status: http.StatusTooManyRequests, Message: "too many requests"}
+	ErrInternal        = &Error{Code: CodeInternal, Status: http.StatusInternalServerError, Message: "internal error"}
+	ErrUnavailable     = &Error{Code: CodeUnavailable, Status: http.StatusServiceUnavailable, Message: "service unavailable"}
+	ErrTimeout         = &Error{Code: CodeTimeout, Status: http.StatusGatewayTimeout, Message: "timeout"}
+)
+
+// Error API error, it is rendered as the JSON body of the response.
+// "error" holds the message to stay compatible with the former {"error": msg} body.
+// Cause is logged but never sent to the client.
 type Error struct {
-	Error string `json:"error"`
+	Code      Code           `json:"code"`
+	Status    int            `json:"-"`
+	Message   string         `json:"error"`
+	Details   map[string]any `json:"details,omitempty"`
+	RequestID string         `json:"request_id,omitempty"`
+	Cause     error          `json:"-"`
+}
+
+// New returns error with the code, its status and the message
+func New(code Code, message string) *Error {
+	return &Error{Code: code, Status: code.Status(), Message: message}
+}
+
+// Newf returns error with the code and formatted message
+func Newf(code Code, format string, args ...any) *Error {
+	return New(code, fmt.Sprintf(format, args...))
+}
+
+// Wrap returns error with the code and the message caused by err.
+// It never returns nil, callers check err first.
+func Wrap(err error, code Code, message string) *Error {
+	e := New(code, message)
+	e.Cause = err
+	return e
+}
+
+// BadRequest returns 400 error
+func BadRequest(message string) *Error {
+	return New(CodeBadRequest, message)
+}
+
+// Unauthorized returns 401 error
+func Unauthorized(message string) *Error {
+	return New(CodeUnauthorized, message)
+}
+
+// Forbidden returns 403 error
+func Forbidden(message string) *Error {
+	return New(CodeForbidden, message)
+}
+
+// NotFound returns 404 error
+func NotFound(message string) *Error {
+	return New(CodeNotFound, message)
+}
+
+// Conflict returns 409 error
+func Conflict(message string) *Error {
+	return New(CodeConflict, message)
+}
+
+// TooManyRequests returns 429 error
+func TooManyRequests(message string) *Error {
+	return New(CodeTooManyRequests, message)
+}
+
+// Unavailable returns 503 error
+func Unavailable(message string) *Error {
+	return New(CodeUnavailable, message)
+}
+
+// Internal returns 500 error with generic message, err is kept as the cause for logs
+func Internal(err error) *Error {
+	e := New(CodeInternal, ErrInternal.Message)
+	e.Cause = err
+	return e
+}
+
+// Validation returns 400 error for request binding failure.
+// Failed fields of validator errors are listed in details as field: tag.
+func Validation(err error) *Error {
+	e := New(CodeValidation, ErrValidation.Message)
+	e.Cause = err
+
+	var fields validator.ValidationErrors
+	if errors.As(err, &fields) {
+		for _, field := range fields {
+			e = e.WithDetail(field.Field(), field.Tag())
+		}
+		return e
+	}
+	if err != nil {
+		e.Message = err.Error()
+	}
+	return e
+}
+
+// Error implements error interface
+func (e *Error) Error() string {
+	if e == nil {
+		return "<nil>"
+	}
+	if e.Cause != nil {
+		return fmt.Sprintf("%s: %s: %v", e.Code, e.Message, e.Cause)
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// Unwrap returns the cause
+func (e *Error) Unwrap() error {
+	if e == nil {
+		return nil
+	}
+	return e.Cause
+}
+
+// Is reports whether target is *Error with the same code
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t != nil && e != nil && t.Code == e.Code
+}
+
+// clone returns copy of the error with its own details
+func (e *Error) clone() *Error {
+	c := *e
+	if e.Details != nil {
+		c.Details = make(map[string]any, len(e.Details))
+		for k, v := range e.Details {
+			c.Details[k] = v
+		}
+	}
+	return &c
+}
+
+// WithDetail returns copy of the error with the detail added
+func (e *Error) WithDetail(key string, value any) *Error {
+	c := e.clone()
+	if c.Details == nil {
+		c.Details = make(map[string]any)
+	}
+	c.Details[key] = value
+	return c
+}
+
+// WithCause returns copy of the error caused by err
+func (e *Error) WithCause(err error) *Error {
+	c := e.clone()
+	c.Cause = err
+	return c
+}
+
+// WithRequestID returns copy of the error with the request ID
+func (e *Error) WithRequestID(id string) *Error {
+	c := e.clone()
+	c.RequestID = id
+	return c
+}
+
+// From maps any error to *Error: *Error in the chain is returned as is,
+// context deadline becomes 504 and anything else 500 with generic message.
+// It never returns nil, callers check err first.
+func From(err error) *Error {
+	var e *Error
+	switch {
+	case errors.As(err, &e) && e != nil:
+		if e.Status == 0 {
+			e = e.clone()
+			e.Status = e.Code.Status()
+		}
+		return e
+	case errors.Is(err, context.DeadlineExceeded):
+		return Wrap(err, CodeTimeout, ErrTimeout.Message)
+	}
+	return Internal(err)
+}
+
+// RequestID returns request ID from gin context key RequestIDKey or header RequestIDHeader
+func RequestID(c *gin.Context) string {
+	if id := c.GetString(RequestIDKey); id != "" {
+		return id
+	}
+	return c.GetHeader(RequestIDHeader)
+}
+
+// level returns log level for the status: Error for 5xx, Info for routine 401, 403 and 404, Warn for other 4xx
+func level(status int) zapcore.Level {
+	switch {
+	case status >= http.StatusInternalServerError:
+		return zapcore.ErrorLevel
+	case status == http.StatusUnauthorized, status == http.StatusForbidden, status == http.StatusNotFound:
+		return zapcore.InfoLevel
+	}
+	return zapcore.WarnLevel
+}
+
+// Respond maps err to status and JSON body, logs it and aborts the request.
+// Nil err is ignored, nil logger disables logging.
+func Respond(c *gin.Context, logger *zap.Logger, err error) {
+	if err == nil {
+		return
+	}
+	e := From(err)
+	if e.RequestID == "" {
+		if id := RequestID(c); id != "" {
+			e = e.WithRequestID(id)
+		}
+	}
+
+	if logger != nil {
+		fields := []zap.Field{
+			zap.String("code", string(e.Code)),
+			zap.Int("status", e.Status),
+			zap.String("method", c.Request.Method),
+			zap.String("path", c.Request.URL.Path),
+			zap.Error(err),
+		}
+		if e.RequestID != "" {
+			fields = append(fields, zap.String("request_id", e.RequestID))
+		}
+		logger.Log(level(e.Status), e.Message, fields...)
+	}
+
+	_ = c.Error(err)
+	c.AbortWithStatusJSON(e.Status, e)
+}
+
+// Handler returns middleware responding with the last error added by handlers via c.Error
+// when nothing has been written yet
+func Handler(logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		if err := c.Errors.Last(); err != nil && !c.Writer.Written() {
+			Respond(c, logger, err.Err)
+		}
+	}
 }
 
+// BindError responds to request binding failure with 400 validation error,
+// typed errors keep their own status
 func BindError(c *gin.Context, logger *zap.Logger, err error) {
-	logger.Error("error", zap.Error(err))
-	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var e *Error
+	if errors.As(err, &e) {
+		Respond(c, logger, err)
+		return
+	}
+	Respond(c, logger, Validation(err))
 }
 
+// SendBadRequest responds with 400 and the error message without logging,
+// typed errors keep their own status
 func SendBadRequest(c *gin.Context, err error) {
-	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var e *Error
+	if errors.As(err, &e) {
+		Respond(c, nil, err)
+		return
+	}
+	Respond(c, nil, BadRequest(err.Error()).WithCause(err))
 }
@@ -0,0 +1,249 @@
+package errs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// serve runs handler for GET /test with the headers and returns the recorder and observed logs
+func serve(handler gin.HandlerFunc, headers map[string]string, middleware ...gin.HandlerFunc) (*httptest.ResponseRecorder, *observer.ObservedLogs) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger := zap.New(core)
+
+	router := gin.New()
+	router.Use(middleware...)
+	router.GET("/test", func(c *gin.Context) {
+		c.Set("logger", logger)
+		handler(c)
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/test", nil)
+	for k, v := range headers {
+		request.Header.Set(k, v)
+	}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder, logs
+}
+
+func decode(t *testing.T, recorder *httptest.ResponseRecorder) map[string]any {
+	t.Helper()
+	var body map[string]any
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	return body
+}
+
+func logger(c *gin.Context) *zap.Logger {
+	return c.MustGet("logger").(*zap.Logger)
+}
+
+func TestErrorWrapping(t *testing.T) {
+	cause := errors.New("sql: no rows")
+	err := fmt.Errorf("load user: %w", Wrap(cause, CodeNotFound, "user not found"))
+
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorIs(t, err, cause)
+	assert.NotErrorIs(t, err, ErrConflict)
+
+	var e *Error
+	assert.True(t, errors.As(err, &e))
+	assert.Equal(t, http.StatusNotFound, e.Status)
+	assert.Equal(t, "not_found: user not found: sql: no rows", e.Error())
+
+	var wrapped error = Wrap(nil, CodeInternal, "no cause")
+	assert.NotNil(t, wrapped)
+	assert.Equal(t, "internal: no cause", wrapped.Error())
+
+	var null *Error
+	assert.False(t, errors.Is(err, null))
+	assert.False(t, null.Is(ErrNotFound))
+	assert.Equal(t, "<nil>", null.Error())
+	assert.Nil(t, null.Unwrap())
+
+	detailed := BadRequest("bad symbol").WithDetail("symbol", "BTC")
+	assert.Equal(t, map[string]any{"symbol": "BTC"}, detailed.Details)
+	assert.Len(t, detailed.WithDetail("side", "Buy").Details, 2)
+	assert.Len(t, detailed.Details, 1)
+}
+
+func TestFrom(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		code    Code
+		status  int
+		message string
+	}{
+		{"typed", Forbidden("no access"), CodeForbidden, http.StatusForbidden, "no access"},
+		{"wrapped", fmt.Errorf("handler: %w", Conflict("exists")), CodeConflict, http.StatusConflict, "exists"},
+		{"sentinel", ErrUnauthorized, CodeUnauthorized, http.StatusUnauthorized, "unauthorized"},
+		{"literal without status", &Error{Code: CodeTooManyRequests, Message: "slow down"}, CodeTooManyRequests, http.StatusTooManyRequests, "slow down"},
+		{"deadline", fmt.Errorf("query: %w", context.DeadlineExceeded), CodeTimeout, http.StatusGatewayTimeout, "timeout"},
+		{"plain", errors.New("connection refused"), CodeInternal, http.StatusInternalServerError, "internal error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := From(tt.err)
+			assert.Equal(t, tt.code, e.Code)
+			assert.Equal(t, tt.status, e.Status)
+			assert.Equal(t, tt.message, e.Message)
+		})
+	}
+	assert.Equal(t, CodeInternal, From(nil).Code)
+	assert.Equal(t, CodeInternal, From(fmt.Errorf("typed nil: %w", (*Error)(nil))).Code)
+}
+
+func TestRespond(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		body   map[string]any
+		level  zapcore.Level
+	}{
+		{
+			name:   "not found",
+			err:    NotFound("order not found").WithDetail("id", "42"),
+			status: http.StatusNotFound,
+			body:   map[string]any{"code": "not_found", "error": "order not found", "details": map[string]any{"id": "42"}, "request_id": "req-1"},
+			level:  zapcore.InfoLevel,
+		},
+		{
+			name:   "conflict",
+			err:    Conflict("order exists"),
+			status: http.StatusConflict,
+			body:   map[string]any{"code": "conflict", "error": "order exists", "request_id": "req-1"},
+			level:  zapcore.WarnLevel,
+		},
+		{
+			name:   "internal hides cause",
+			err:    errors.New("password=secret"),
+			status: http.StatusInternalServerError,
+			body:   map[string]any{"code": "internal", "error": "internal error", "request_id": "req-1"},
+			level:  zapcore.ErrorLevel,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder, logs := serve(func(c *gin.Context) {
+				Respond(c, logger(c), tt.err)
+			}, map[string]string{RequestIDHeader: "req-1"})
+
+			assert.Equal(t, tt.status, recorder.Code)
+			assert.Equal(t, tt.body, decode(t, recorder))
+			assert.Equal(t, 1, logs.Len())
+			entry := logs.All()[0]
+			assert.Equal(t, tt.level, entry.Level)
+			assert.Equal(t, "req-1", entry.ContextMap()["request_id"])
+			assert.Contains(t, entry.ContextMap()["error"], tt.err.Error())
+		})
+	}
+}
+
+func TestRespondRequestIDFromContext(t *testing.T) {
+	recorder, _ := serve(func(c *gin.Context) {
+		c.Set(RequestIDKey, "ctx-id")
+		Respond(c, nil, Unauthorized("token expired"))
+	}, map[string]string{RequestIDHeader: "header-id"})
+
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	assert.Equal(t, "ctx-id", decode(t, recorder)["request_id"])
+}
+
+func TestHandler(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	recorder, _ := serve(func(c *gin.Context) {
+		_ = c.Error(fmt.Errorf("get order: %w", NotFound("order not found")))
+	}, nil, Handler(zap.New(core)))
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	assert.Equal(t, "order not found", decode(t, recorder)["error"])
+	assert.Equal(t, 1, logs.Len())
+
+	recorder, _ = serve(func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	}, nil, Handler(zap.New(core)))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestBindError(t *testing.T) {
+	type order struct {
+		Symbol string  `json:"symbol" binding:"required"`
+		Qty    float64 `json:"qty" binding:"gt=0"`
+	}
+
+	router := gin.New()
+	router.POST("/order", func(c *gin.Context) {
+		var o order
+		if err := c.ShouldBindJSON(&o); err != nil {
+			BindError(c, zap.NewNop(), err)
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+
+	tests := []struct {
+		name    string
+		body    string
+		message string
+		details map[string]any
+	}{
+		{"validation", `{"qty": -1}`, "validation failed", map[string]any{"Symbol": "required", "Qty": "gt"}},
+		{"syntax", `{`, "unexpected EOF", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/order", strings.NewReader(tt.body)))
+
+			assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			body := decode(t, recorder)
+			assert.Equal(t, "validation_failed", body["code"])
+			assert.Equal(t, tt.message, body["error"])
+			if tt.details != nil {
+				assert.Equal(t, tt.details, body["details"])
+			}
+		})
+	}
+}
+
+func TestSendBadRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		status  int
+		message string
+	}{
+		{"plain", errors.New("invalid timeframe"), http.StatusBadRequest, "invalid timeframe"},
+		{"typed", Forbidden("read only key"), http.StatusForbidden, "read only key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder, _ := serve(func(c *gin.Context) {
+				SendBadRequest(c, tt.err)
+			}, nil)
+
+			assert.Equal(t, tt.status, recorder.Code)
+			assert.Equal(t, tt.message, decode(t, recorder)["error"])
+		})
+	}
+}
@@ -0,0 +1,137 @@
+// Package export writes time-indexed multi-column series, e.g. candles with indicators, row by row
+// into an io.Writer as CSV, JSON Lines, ClickHouse TabSeparatedWithNames or Parquet,
+// without building the whole table in memory.
+package export
+
+import (
+	"errors"
+	"fmt"
+	"github.com/AlexanderKolesnkov/golang-utils-stuff/consts"
+	"io"
+	"os"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrColumnCount   = errors.New("values do not match the columns")
+	ErrClosed        = errors.New("writer is closed")
+	ErrUnknownFormat = errors.New("unknown format")
+)
+
+// Writer writes rows of a time and one value per column. Close flushes buffered rows
+// and writes the trailer of the format, it does not close the underlying io.Writer.
+type Writer interface {
+	WriteRow(t time.Time, values ...float64) error
+	Close() error
+}
+
+// Options of the writers, the zero value with Columns is ready to use
+type Options struct {
+	// TimeColumn name of the time column, "Time" by default
+	TimeColumn string
+	// Columns names of the value columns
+	Columns []string
+	// FormatTime formats time of CSV, JSON Lines and TSV, consts.TimeLayout by default
+	FormatTime func(t time.Time) string
+	// FormatFloat formats values of CSV and TSV, strconv 'f' with the shortest precision by default
+	FormatFloat func(v float64) string
+	// Comma field delimiter of CSV, ',' by default, e.g. ';' for spreadsheets with "," decimal separator
+	Comma rune
+}
+
+// withDefaults returns options with the default hooks for empty fields
+func (o Options) withDefaults() Options {
+	if o.TimeColumn == "" {
+		o.TimeColumn = consts.SingleIndicatorHeader()[0]
+	}
+	if o.FormatTime == nil {
+		o.FormatTime = FormatTime
+	}
+	if o.FormatFloat == nil {
+		o.FormatFloat = FormatFloat
+	}
+	return o
+}
+
+// header returns the time column followed by the value columns
+func (o Options) header() []string {
+	return append([]string{o.TimeColumn}, o.Columns...)
+}
+
+// FormatTime formats time in UTC by consts.TimeLayout
+func FormatTime(t time.Time) string {
+	return t.UTC().Format(consts.TimeLayout)
+}
+
+// FormatFloat formats value with the shortest precision without exponent
+func FormatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// WriteSeries writes columnar series of equal length as rows and closes the writer
+func WriteSeries(w Writer, times []time.Time, columns ...[]float64) error {
+	for _, column := range columns {
+		if len(column) != len(times) {
+			return ErrColumnCount
+		}
+	}
+
+	values := make([]float64, len(columns))
+	for i, t := range times {
+		for j, column := range columns {
+			values[j] = column[i]
+		}
+		if err := w.WriteRow(t, values...); err != nil {
+			return err
+		}
+	}
+	return w.Close()
+}
+
+// Format output format of NewWriter
+type Format int
+
+const (
+	CSV Format = iota
+	JSONL
+	TSV
+	Parquet
+)
+
+// NewWriter returns writer of the format, ErrUnknownFormat for values other than the Format constants
+func NewWriter(w io.Writer, format Format, options Options) (Writer, error) {
+	switch format {
+	case CSV:
+		return NewCSVWriter(w, options)
+	case JSONL:
+		return NewJSONLWriter(w, options)
+	case TSV:
+		return NewTSVWriter(w, options)
+	case Parquet:
+		return NewParquetWriter(w, options)
+	}
+	return nil, fmt.Errorf("%w: %d", ErrUnknownFormat, format)
+}
+
+// WriteFile writes columnar series into the file at path as is, unlike utils.WriteCsv no extension is appended
+func WriteFile(path string, format Format, options Options, times []time.Time, columns ...[]float64) error {
+	if format < CSV || format > Parquet {
+		return fmt.Errorf("%w: %d", ErrUnknownFormat, format)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	w, err := NewWriter(file, format, options)
+	if err != nil {
+		return err
+	}
+	if err := WriteSeries(w, times, columns...); err != nil {
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,110 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	exportTimes = []time.Time{
+		time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 1, 0, 1, 0, 0, time.UTC),
+		time.Date(2024, 1, 1, 0, 2, 0, 0, time.UTC),
+	}
+	exportClose = []float64{100.5, 101, math.NaN()}
+	exportSma   = []float64{math.NaN(), 100.75, math.Inf(1)}
+)
+
+func TestCSVWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewCSVWriter(&buf, Options{Columns: []string{"Close", "Sma"}})
+	assert.NoError(t, err)
+	assert.NoError(t, WriteSeries(w, exportTimes, exportClose, exportSma))
+
+	assert.Equal(t, ""+
+		"Time,Close,Sma\n"+
+		"2024-01-01 00:00:00,100.5,NaN\n"+
+		"2024-01-01 00:01:00,101,100.75\n"+
+		"2024-01-01 00:02:00,NaN,+Inf\n", buf.String())
+
+	assert.ErrorIs(t, w.WriteRow(exportTimes[0], 1, 2), ErrClosed)
+}
+
+func TestCSVWriterHooks(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewCSVWriter(&buf, Options{
+		TimeColumn:  "ts",
+		Columns:     []string{"value"},
+		FormatTime:  func(t time.Time) string { return t.Format(time.RFC3339) },
+		FormatFloat: func(v float64) string { return strings.Replace(FormatFloat(v), ".", ",", 1) },
+		Comma:       ';',
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, w.WriteRow(exportTimes[0], 1.5))
+	assert.ErrorIs(t, w.WriteRow(exportTimes[0]), ErrColumnCount)
+	assert.NoError(t, w.Close())
+
+	assert.Equal(t, "ts;value\n2024-01-01T00:00:00Z;1,5\n", buf.String())
+}
+
+func TestJSONLWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewJSONLWriter(&buf, Options{Columns: []string{"Close", `"quoted"`}})
+	assert.NoError(t, err)
+	assert.NoError(t, WriteSeries(w, exportTimes, exportClose, []float64{1e-9, 1e21, -0.25}))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 3)
+	assert.Equal(t, `{"Time":"2024-01-01 00:00:00","Close":100.5,"\"quoted\"":1e-09}`, lines[0])
+	assert.Equal(t, `{"Time":"2024-01-01 00:02:00","Close":null,"\"quoted\"":-0.25}`, lines[2])
+	for _, line := range lines {
+		assert.True(t, json.Valid([]byte(line)), line)
+	}
+}
+
+func TestTSVWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewTSVWriter(&buf, Options{Columns: []string{"close\tprice", "sma"}})
+	assert.NoError(t, err)
+	assert.NoError(t, WriteSeries(w, exportTimes, exportClose, []float64{math.NaN(), math.Inf(-1), 2}))
+
+	assert.Equal(t, ""+
+		"Time\tclose\\tprice\tsma\n"+
+		"2024-01-01 00:00:00\t100.5\tnan\n"+
+		"2024-01-01 00:01:00\t101\t-inf\n"+
+		"2024-01-01 00:02:00\tnan\t2\n", buf.String())
+}
+
+func TestWriteSeriesLengthMismatch(t *testing.T) {
+	var buf bytes.Buffer
+	w, _ := NewCSVWriter(&buf, Options{Columns: []string{"a"}})
+	assert.ErrorIs(t, WriteSeries(w, exportTimes, []float64{1}), ErrColumnCount)
+}
+
+func TestWriteFile(t *testing.T) {
+	dir := t.TempDir()
+	for _, format := range []Format{CSV, JSONL, TSV, Parquet} {
+		path := filepath.Join(dir, "series.out")
+		assert.NoError(t, WriteFile(path, format, Options{Columns: []string{"Close"}}, exportTimes, exportClose))
+
+		content, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		var buf bytes.Buffer
+		w, _ := NewWriter(&buf, format, Options{Columns: []string{"Close"}})
+		assert.NoError(t, WriteSeries(w, exportTimes, exportClose))
+		assert.Equal(t, buf.Bytes(), content, "the path is used as is")
+	}
+
+	_, err := NewWriter(&bytes.Buffer{}, Format(42), Options{Columns: []string{"Close"}})
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+	unknown := filepath.Join(dir, "unknown.out")
+	assert.ErrorIs(t, WriteFile(unknown, Format(-1), Options{Columns: []string{"Close"}}, exportTimes, exportClose), ErrUnknownFormat)
+	assert.NoFileExists(t, unknown)
+}
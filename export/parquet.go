@@ -0,0 +1,242 @@
+package export
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"time"
+)
+
+// Parquet constants of parquet.thrift
+const (
+	parquetInt64           = 2
+	parquetDouble          = 5
+	parquetRequired        = 0
+	parquetTimestampMillis = 9
+	parquetPlain           = 0
+	parquetRLE             = 3
+	parquetUncompressed    = 0
+	parquetDataPage        = 0
+)
+
+var parquetMagic = []byte("PAR1")
+
+// DefaultRowGroupSize rows buffered by ParquetWriter before a row group is written
+const DefaultRowGroupSize = 100000
+
+// parquetChunk location of a written column chunk
+type parquetChunk struct {
+	offset int64
+	size   int64
+	values int64
+}
+
+// parquetRowGroup written row group
+type parquetRowGroup struct {
+	rows   int64
+	chunks []parquetChunk
+}
+
+// ParquetWriter writes Parquet file with the time column as INT64 TIMESTAMP_MILLIS and DOUBLE value columns,
+// all required, PLAIN encoded and uncompressed. Rows are buffered by columns and written as row groups
+// of RowGroupSize rows, the footer is written by Close. Hooks of Options are not used.
+type ParquetWriter struct {
+	options Options
+	w       io.Writer
+	offset  int64
+
+	// RowGroupSize rows of a row group, DefaultRowGroupSize when not positive
+	RowGroupSize int
+
+	times     []int64
+	columns   [][]float64
+	rowGroups []parquetRowGroup
+	closed    bool
+}
+
+// NewParquetWriter writes the magic bytes and returns writer of the rows
+func NewParquetWriter(w io.Writer, options Options) (*ParquetWriter, error) {
+	p := &ParquetWriter{options: options.withDefaults(), w: w, columns: make([][]float64, len(options.Columns))}
+	if err := p.write(parquetMagic); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *ParquetWriter) write(b []byte) error {
+	n, err := p.w.Write(b)
+	p.offset += int64(n)
+	return err
+}
+
+// WriteRow buffers the row and writes the row group when it is full
+func (p *ParquetWriter) WriteRow(t time.Time, values ...float64) error {
+	if p.closed {
+		return ErrClosed
+	}
+	if len(values) != len(p.columns) {
+		return ErrColumnCount
+	}
+
+	p.times = append(p.times, t.UnixMilli())
+	for i, v := range values {
+		p.columns[i] = append(p.columns[i], v)
+	}
+
+	size := p.RowGroupSize
+	if size <= 0 {
+		size = DefaultRowGroupSize
+	}
+	if len(p.times) >= size {
+		return p.flush()
+	}
+	return nil
+}
+
+// flush writes buffered rows as a row group with one data page per column
+func (p *ParquetWriter) flush() error {
+	if len(p.times) == 0 {
+		return nil
+	}
+
+	rows := len(p.times)
+	group := parquetRowGroup{rows: int64(rows)}
+	page := make([]byte, 8*rows)
+
+	for i, v := range p.times {
+		binary.LittleEndian.PutUint64(page[8*i:], uint64(v))
+	}
+	chunk, err := p.writePage(page, rows)
+	if err != nil {
+		return err
+	}
+	group.chunks = append(group.chunks, chunk)
+
+	for c, column := range p.columns {
+		for i, v := range column {
+			binary.LittleEndian.PutUint64(page[8*i:], math.Float64bits(v))
+		}
+		chunk, err := p.writePage(page, rows)
+		if err != nil {
+			return err
+		}
+		group.chunks = append(group.chunks, chunk)
+		p.columns[c] = column[:0]
+	}
+
+	p.times = p.times[:0]
+	p.rowGroups = append(p.rowGroups, group)
+	return nil
+}
+
+// writePage writes data page of plain encoded required values as a column chunk
+func (p *ParquetWriter) writePage(data []byte, values int) (parquetChunk, error) {
+	var header thriftWriter
+	header.begin()
+	header.i32(1, parquetDataPage)
+	header.i32(2, int32(len(data)))
+	header.i32(3, int32(len(data)))
+	header.structField(5)
+	header.i32(1, int32(values))
+	header.i32(2, parquetPlain)
+	header.i32(3, parquetRLE)
+	header.i32(4, parquetRLE)
+	header.end()
+	header.end()
+
+	chunk := parquetChunk{offset: p.offset, size: int64(len(header.buf) + len(data)), values: int64(values)}
+	if err := p.write(header.buf); err != nil {
+		return chunk, err
+	}
+	return chunk, p.write(data)
+}
+
+// Close writes the buffered rows and the footer
+func (p *ParquetWriter) Close() error {
+	if p.closed {
+		return nil
+	}
+	p.closed = true
+	if err := p.flush(); err != nil {
+		return err
+	}
+
+	footer := p.footer()
+	if err := p.write(footer); err != nil {
+		return err
+	}
+	if err := p.write(binary.LittleEndian.AppendUint32(nil, uint32(len(footer)))); err != nil {
+		return err
+	}
+	return p.write(parquetMagic)
+}
+
+// footer returns FileMetaData of the written row groups
+func (p *ParquetWriter) footer() []byte {
+	names := p.options.header()
+	types := make([]int32, len(names))
+	types[0] = parquetInt64
+	for i := 1; i < len(types); i++ {
+		types[i] = parquetDouble
+	}
+
+	var rows int64
+	for _, group := range p.rowGroups {
+		rows += group.rows
+	}
+
+	var w thriftWriter
+	w.begin()
+	w.i32(1, 1) // version
+
+	w.list(2, thriftStruct, len(names)+1)
+	w.begin()
+	w.string(4, "schema")
+	w.i32(5, int32(len(names)))
+	w.end()
+	for i, name := range names {
+		w.begin()
+		w.i32(1, types[i])
+		w.i32(3, parquetRequired)
+		w.string(4, name)
+		if i == 0 {
+			w.i32(6, parquetTimestampMillis)
+		}
+		w.end()
+	}
+
+	w.i64(3, rows)
+
+	w.list(4, thriftStruct, len(p.rowGroups))
+	for _, group := range p.rowGroups {
+		w.begin()
+		var size int64
+		w.list(1, thriftStruct, len(group.chunks))
+		for i, chunk := range group.chunks {
+			size += chunk.size
+			w.begin()
+			w.i64(2, chunk.offset)
+			w.structField(3)
+			w.i32(1, types[i])
+			w.list(2, thriftI32, 1)
+			w.zigzag(parquetPlain)
+			w.list(3, thriftBinary, 1)
+			w.varint(uint64(len(names[i])))
+			w.buf = append(w.buf, names[i]...)
+			w.i32(4, parquetUncompressed)
+			w.i64(5, chunk.values)
+			w.i64(6, chunk.size)
+			w.i64(7, chunk.size)
+			w.i64(9, chunk.offset)
+			w.end()
+			w.end()
+		}
+		w.i64(2, size)
+		w.i64(3, group.rows)
+		w.end()
+	}
+
+	w.string(6, "golang-utils-stuff export")
+	w.end()
+	return w.buf
+}
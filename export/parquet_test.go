@@ -0,0 +1,173 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"flag"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"os"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files in testdata")
+
+// thriftReader decodes Thrift compact structs into maps of field id to value:
+// int64 for integers, string for binary, []any for lists and map[int16]any for structs
+type thriftReader struct {
+	buf []byte
+	pos int
+}
+
+func (r *thriftReader) varint() uint64 {
+	v, n := binary.Uvarint(r.buf[r.pos:])
+	r.pos += n
+	return v
+}
+
+func (r *thriftReader) zigzag() int64 {
+	v := r.varint()
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (r *thriftReader) value(kind byte) any {
+	switch kind {
+	case thriftI32, thriftI64:
+		return r.zigzag()
+	case thriftBinary:
+		n := int(r.varint())
+		r.pos += n
+		return string(r.buf[r.pos-n : r.pos])
+	case thriftList:
+		header := r.buf[r.pos]
+		r.pos++
+		size := int(header >> 4)
+		if size == 15 {
+			size = int(r.varint())
+		}
+		list := make([]any, size)
+		for i := range list {
+			list[i] = r.value(header & 0x0f)
+		}
+		return list
+	case thriftStruct:
+		return r.structValue()
+	}
+	panic("unexpected thrift type")
+}
+
+func (r *thriftReader) structValue() map[int16]any {
+	fields := map[int16]any{}
+	var id int16
+	for {
+		header := r.buf[r.pos]
+		r.pos++
+		if header == 0 {
+			return fields
+		}
+		if delta := int16(header >> 4); delta != 0 {
+			id += delta
+		} else {
+			id = int16(r.zigzag())
+		}
+		fields[id] = r.value(header & 0x0f)
+	}
+}
+
+func TestParquetWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewParquetWriter(&buf, Options{Columns: []string{"Close", "Sma"}})
+	assert.NoError(t, err)
+	w.RowGroupSize = 2
+	assert.NoError(t, WriteSeries(w, exportTimes, exportClose, exportSma))
+
+	file := buf.Bytes()
+	assert.Equal(t, []byte("PAR1"), file[:4])
+	assert.Equal(t, []byte("PAR1"), file[len(file)-4:])
+
+	length := int(binary.LittleEndian.Uint32(file[len(file)-8:]))
+	footer := &thriftReader{buf: file[len(file)-8-length : len(file)-8]}
+	meta := footer.structValue()
+	assert.Equal(t, length, footer.pos, "footer is decoded completely")
+	assert.Equal(t, int64(3), meta[3], "num_rows")
+
+	schema := meta[2].([]any)
+	assert.Len(t, schema, 4)
+	assert.Equal(t, int64(3), schema[0].(map[int16]any)[5])
+	assert.Equal(t, "Time", schema[1].(map[int16]any)[4])
+	assert.Equal(t, int64(parquetTimestampMillis), schema[1].(map[int16]any)[6])
+	assert.Equal(t, int64(parquetDouble), schema[3].(map[int16]any)[1])
+
+	rowGroups := meta[4].([]any)
+	assert.Len(t, rowGroups, 2)
+	var times []int64
+	var sma []float64
+	for _, group := range rowGroups {
+		columns := group.(map[int16]any)[1].([]any)
+		assert.Len(t, columns, 3)
+		rows := int(group.(map[int16]any)[3].(int64))
+
+		for c, column := range columns {
+			meta := column.(map[int16]any)[3].(map[int16]any)
+			assert.Equal(t, []string{"Time", "Close", "Sma"}[c], meta[3].([]any)[0])
+
+			page := &thriftReader{buf: file[meta[9].(int64):]}
+			header := page.structValue()
+			assert.Equal(t, int64(rows), header[5].(map[int16]any)[1])
+			assert.Equal(t, meta[6], int64(page.pos)+header[2].(int64), "chunk size is header plus data")
+
+			data := page.buf[page.pos : page.pos+int(header[2].(int64))]
+			for i := 0; i < rows; i++ {
+				v := binary.LittleEndian.Uint64(data[8*i:])
+				switch c {
+				case 0:
+					times = append(times, int64(v))
+				case 2:
+					sma = append(sma, math.Float64frombits(v))
+				}
+			}
+		}
+	}
+
+	for i, ts := range exportTimes {
+		assert.Equal(t, ts.UnixMilli(), times[i])
+	}
+	assert.True(t, math.IsNaN(sma[0]))
+	assert.Equal(t, exportSma[1:], sma[1:])
+}
+
+// TestParquetGolden compares the output with testdata/series.parquet, the file was read back with
+// the column reader of github.com/xitongsys/parquet-go v1.6.2: 3 rows in 2 row groups,
+// Time as INT64 TIMESTAMP_MILLIS, Close [100.5 101 NaN] and Sma [NaN 100.75 +Inf] as DOUBLE.
+// A change of the encoding must be checked with a Parquet reader again before running with -update.
+func TestParquetGolden(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewParquetWriter(&buf, Options{Columns: []string{"Close", "Sma"}})
+	assert.NoError(t, err)
+	w.RowGroupSize = 2
+	assert.NoError(t, WriteSeries(w, exportTimes, exportClose, exportSma))
+
+	const golden = "testdata/series.parquet"
+	if *update {
+		assert.NoError(t, os.WriteFile(golden, buf.Bytes(), 0o644))
+	}
+	want, err := os.ReadFile(golden)
+	assert.NoError(t, err)
+	assert.Equal(t, want, buf.Bytes())
+}
+
+func TestParquetWriterEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewParquetWriter(&buf, Options{Columns: []string{"v"}})
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+	assert.NoError(t, w.Close())
+	assert.ErrorIs(t, w.WriteRow(exportTimes[0], 1), ErrClosed)
+
+	file := buf.Bytes()
+	length := int(binary.LittleEndian.Uint32(file[len(file)-8:]))
+	assert.Equal(t, len(file), 4+length+8)
+	meta := (&thriftReader{buf: file[4 : 4+length]}).structValue()
+	assert.Equal(t, int64(0), meta[3])
+	assert.Empty(t, meta[4])
+}
@@ -0,0 +1,199 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CSVWriter writes the header and comma separated rows
+type CSVWriter struct {
+	options Options
+	csv     *csv.Writer
+	record  []string
+	closed  bool
+}
+
+// NewCSVWriter writes the header and returns writer of the rows
+func NewCSVWriter(w io.Writer, options Options) (*CSVWriter, error) {
+	options = options.withDefaults()
+	c := &CSVWriter{options: options, csv: csv.NewWriter(w), record: make([]string, len(options.Columns)+1)}
+	if options.Comma != 0 {
+		c.csv.Comma = options.Comma
+	}
+	if err := c.csv.Write(options.header()); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// WriteRow writes the row formatted by the hooks
+func (c *CSVWriter) WriteRow(t time.Time, values ...float64) error {
+	if c.closed {
+		return ErrClosed
+	}
+	if len(values) != len(c.options.Columns) {
+		return ErrColumnCount
+	}
+
+	c.record[0] = c.options.FormatTime(t)
+	for i, v := range values {
+		c.record[i+1] = c.options.FormatFloat(v)
+	}
+	return c.csv.Write(c.record)
+}
+
+// Close flushes buffered rows
+func (c *CSVWriter) Close() error {
+	c.closed = true
+	c.csv.Flush()
+	return c.csv.Error()
+}
+
+// JSONLWriter writes one JSON object per row: {"Time":"2024-01-01 00:00:00","sma":1.5}.
+// NaN and infinities are not valid JSON numbers and are written as null, FormatFloat is not used.
+type JSONLWriter struct {
+	options Options
+	w       *bufio.Writer
+	keys    [][]byte // quoted column names with the colon
+	line    []byte
+	closed  bool
+}
+
+// NewJSONLWriter returns writer of JSON Lines
+func NewJSONLWriter(w io.Writer, options Options) (*JSONLWriter, error) {
+	options = options.withDefaults()
+	j := &JSONLWriter{options: options, w: bufio.NewWriter(w)}
+	for _, name := range options.header() {
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		j.keys = append(j.keys, append(key, ':'))
+	}
+	return j, nil
+}
+
+// WriteRow writes the row as a JSON object
+func (j *JSONLWriter) WriteRow(t time.Time, values ...float64) error {
+	if j.closed {
+		return ErrClosed
+	}
+	if len(values) != len(j.options.Columns) {
+		return ErrColumnCount
+	}
+
+	line := append(j.line[:0], '{')
+	line = append(line, j.keys[0]...)
+	line = strconv.AppendQuote(line, j.options.FormatTime(t))
+	for i, v := range values {
+		line = append(line, ',')
+		line = append(line, j.keys[i+1]...)
+		line = appendJSONFloat(line, v)
+	}
+	line = append(line, '}', '\n')
+	j.line = line
+
+	_, err := j.w.Write(line)
+	return err
+}
+
+// appendJSONFloat appends value like encoding/json does, null for NaN and infinities
+func appendJSONFloat(b []byte, v float64) []byte {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return append(b, "null"...)
+	}
+	format := byte('f')
+	if abs := math.Abs(v); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		format = 'e'
+	}
+	return strconv.AppendFloat(b, v, format, -1, 64)
+}
+
+// Close flushes buffered rows
+func (j *JSONLWriter) Close() error {
+	j.closed = true
+	return j.w.Flush()
+}
+
+// tsvEscaper escapes values of ClickHouse TabSeparated format
+var tsvEscaper = strings.NewReplacer("\\", "\\\\", "\t", "\\t", "\n", "\\n", "\r", "\\r")
+
+// TSVWriter writes ClickHouse TabSeparatedWithNames: the header and tab separated rows,
+// e.g. clickhouse-client --query "INSERT INTO t FORMAT TabSeparatedWithNames" < file.tsv.
+// The default time layout matches DateTime, NaN and infinities are written as nan, inf and -inf.
+type TSVWriter struct {
+	options Options
+	w       *bufio.Writer
+	closed  bool
+}
+
+// NewTSVWriter writes the header and returns writer of the rows
+func NewTSVWriter(w io.Writer, options Options) (*TSVWriter, error) {
+	options = options.withDefaults()
+	t := &TSVWriter{options: options, w: bufio.NewWriter(w)}
+	if err := t.writeLine(options.header()...); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (t *TSVWriter) writeLine(fields ...string) error {
+	for i, field := range fields {
+		if i > 0 {
+			if err := t.w.WriteByte('\t'); err != nil {
+				return err
+			}
+		}
+		if _, err := tsvEscaper.WriteString(t.w, field); err != nil {
+			return err
+		}
+	}
+	return t.w.WriteByte('\n')
+}
+
+// WriteRow writes the row formatted by the hooks
+func (t *TSVWriter) WriteRow(ts time.Time, values ...float64) error {
+	if t.closed {
+		return ErrClosed
+	}
+	if len(values) != len(t.options.Columns) {
+		return ErrColumnCount
+	}
+
+	if _, err := tsvEscaper.WriteString(t.w, t.options.FormatTime(ts)); err != nil {
+		return err
+	}
+	for _, v := range values {
+		if err := t.w.WriteByte('\t'); err != nil {
+			return err
+		}
+		if _, err := tsvEscaper.WriteString(t.w, t.formatFloat(v)); err != nil {
+			return err
+		}
+	}
+	return t.w.WriteByte('\n')
+}
+
+func (t *TSVWriter) formatFloat(v float64) string {
+	switch {
+	case math.IsNaN(v):
+		return "nan"
+	case math.IsInf(v, 1):
+		return "inf"
+	case math.IsInf(v, -1):
+		return "-inf"
+	}
+	return t.options.FormatFloat(v)
+}
+
+// Close flushes buffered rows
+func (t *TSVWriter) Close() error {
+	t.closed = true
+	return t.w.Flush()
+}
@@ -0,0 +1,79 @@
+package export
+
+import "encoding/binary"
+
+// Thrift compact protocol types used by Parquet metadata
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// thriftWriter encodes structs in Thrift compact protocol, fields must be written in increasing id order
+type thriftWriter struct {
+	buf     []byte
+	lastIDs []int16 // last field id of every open struct
+}
+
+func (w *thriftWriter) varint(v uint64) {
+	w.buf = binary.AppendUvarint(w.buf, v)
+}
+
+func (w *thriftWriter) zigzag(v int64) {
+	w.varint(uint64((v << 1) ^ (v >> 63)))
+}
+
+func (w *thriftWriter) field(id int16, kind byte) {
+	last := &w.lastIDs[len(w.lastIDs)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		w.buf = append(w.buf, byte(delta)<<4|kind)
+	} else {
+		w.buf = append(w.buf, kind)
+		w.zigzag(int64(id))
+	}
+	*last = id
+}
+
+func (w *thriftWriter) begin() {
+	w.lastIDs = append(w.lastIDs, 0)
+}
+
+func (w *thriftWriter) end() {
+	w.buf = append(w.buf, 0) // stop field
+	w.lastIDs = w.lastIDs[:len(w.lastIDs)-1]
+}
+
+func (w *thriftWriter) i32(id int16, v int32) {
+	w.field(id, thriftI32)
+	w.zigzag(int64(v))
+}
+
+func (w *thriftWriter) i64(id int16, v int64) {
+	w.field(id, thriftI64)
+	w.zigzag(v)
+}
+
+func (w *thriftWriter) string(id int16, v string) {
+	w.field(id, thriftBinary)
+	w.varint(uint64(len(v)))
+	w.buf = append(w.buf, v...)
+}
+
+// list writes the list header, elements follow without field headers
+func (w *thriftWriter) list(id int16, kind byte, size int) {
+	w.field(id, thriftList)
+	if size < 15 {
+		w.buf = append(w.buf, byte(size)<<4|kind)
+		return
+	}
+	w.buf = append(w.buf, 0xf0|kind)
+	w.varint(uint64(size))
+}
+
+// structField opens nested struct field, it is closed by end
+func (w *thriftWriter) structField(id int16) {
+	w.field(id, thriftStruct)
+	w.begin()
+}
@@ -0,0 +1,146 @@
+// Package chart renders series for terminals as sparklines and ASCII line charts
+// and as standalone SVG with candlesticks, overlay lines and cross markers.
+package chart
+
+import (
+	"github.com/AlexanderKolesnkov/golang-utils-stuff/formatters"
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+var sparkLevels = []rune("▁▂▃▄▅▆▇█")
+
+// bounds returns min and max of finite values of all series, false when there are none
+func bounds(series ...[]float64) (lo, hi float64, ok bool) {
+	lo, hi = math.Inf(1), math.Inf(-1)
+	for _, values := range series {
+		for _, v := range values {
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				continue
+			}
+			lo, hi = math.Min(lo, v), math.Max(hi, v)
+		}
+	}
+	return lo, hi, lo <= hi
+}
+
+// Sparkline renders values as one line of block characters from ▁ to █,
+// NaN and infinities are spaces and a flat series is drawn in the middle
+func Sparkline(values []float64) string {
+	lo, hi, _ := bounds(values)
+	top := len(sparkLevels) - 1
+
+	var b strings.Builder
+	for _, v := range values {
+		switch {
+		case math.IsNaN(v) || math.IsInf(v, 0):
+			b.WriteRune(' ')
+		case hi == lo:
+			b.WriteRune(sparkLevels[top/2])
+		default:
+			b.WriteRune(sparkLevels[int(math.Round((v-lo)/(hi-lo)*float64(top)))])
+		}
+	}
+	return b.String()
+}
+
+// ASCIIOptions configure ASCII, the zero value draws 10 rows with a column per value
+type ASCIIOptions struct {
+	// Height rows of the plot, 10 by default
+	Height int
+	// Width columns of the plot, longer series are resampled to the last value of every column; 0 keeps the length
+	Width int
+	// Glyphs of the series in order, "*+ox#" by default
+	Glyphs string
+	// Labels format of the axis labels, two decimals in LocaleEN by default
+	Labels *formatters.Format
+}
+
+// resample returns width values taking the last value falling into every column
+func resample(values []float64, width int) []float64 {
+	if width <= 0 || len(values) <= width {
+		return values
+	}
+	output := make([]float64, width)
+	for i := range output {
+		output[i] = values[(i+1)*len(values)/width-1]
+	}
+	return output
+}
+
+// ASCII renders series as line chart with the axis labels on the left. Every series is drawn with its glyph,
+// consecutive points are joined by vertical bars, NaN values leave gaps. Later series overwrite earlier ones.
+func ASCII(options ASCIIOptions, series ...[]float64) string {
+	height := options.Height
+	if height <= 0 {
+		height = 10
+	}
+	glyphs := []rune(options.Glyphs)
+	if len(glyphs) == 0 {
+		glyphs = []rune("*+ox#")
+	}
+	labels := formatters.Format{Locale: formatters.LocaleEN, Precision: 2}
+	if options.Labels != nil {
+		labels = *options.Labels
+	}
+
+	width := 0
+	resampled := make([][]float64, len(series))
+	for i, values := range series {
+		resampled[i] = resample(values, options.Width)
+		width = max(width, len(resampled[i]))
+	}
+	lo, hi, ok := bounds(resampled...)
+	if !ok || width == 0 {
+		return ""
+	}
+
+	row := func(v float64) int {
+		if hi == lo {
+			return height / 2
+		}
+		return int(math.Round((hi - v) / (hi - lo) * float64(height-1)))
+	}
+
+	grid := make([][]rune, height)
+	for r := range grid {
+		grid[r] = []rune(strings.Repeat(" ", width))
+	}
+	for s, values := range resampled {
+		glyph := glyphs[s%len(glyphs)]
+		previous := -1
+		for x, v := range values {
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				previous = -1
+				continue
+			}
+			r := row(v)
+			if previous >= 0 {
+				for between := min(r, previous) + 1; between < max(r, previous); between++ {
+					grid[between][x] = '|'
+				}
+			}
+			grid[r][x] = glyph
+			previous = r
+		}
+	}
+
+	axis := make([]string, height)
+	labelWidth := 0
+	for r := range axis {
+		value := hi
+		if height > 1 {
+			value = hi - (hi-lo)*float64(r)/float64(height-1)
+		}
+		axis[r] = labels.Format(value)
+		labelWidth = max(labelWidth, utf8.RuneCountInString(axis[r]))
+	}
+
+	lines := make([]string, height)
+	for r := range grid {
+		padding := strings.Repeat(" ", labelWidth-utf8.RuneCountInString(axis[r]))
+		lines[r] = strings.TrimRight(padding+axis[r]+" ┤"+string(grid[r]), " ")
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,120 @@
+package chart
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"github.com/AlexanderKolesnkov/golang-utils-stuff/formatters"
+	"github.com/AlexanderKolesnkov/golang-utils-stuff/himath"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var nan = math.NaN()
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		want   string
+	}{
+		{name: "levels", values: []float64{1, 2, 3, 4, 5, 6, 7, 8}, want: "▁▂▃▄▅▆▇█"},
+		{name: "gaps", values: []float64{0, nan, 10, math.Inf(1)}, want: "▁ █ "},
+		{name: "flat", values: []float64{5, 5}, want: "▄▄"},
+		{name: "empty", values: nil, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Sparkline(tt.values))
+		})
+	}
+}
+
+func TestASCII(t *testing.T) {
+	assert.Equal(t, ""+
+		"3.00 ┤  *\n"+
+		"2.00 ┤ *\n"+
+		"1.00 ┤*",
+		ASCII(ASCIIOptions{Height: 3}, []float64{1, 2, 3}))
+
+	assert.Equal(t, ""+
+		"3.00 ┤ *\n"+
+		"2.00 ┤ |\n"+
+		"1.00 ┤*",
+		ASCII(ASCIIOptions{Height: 3}, []float64{1, 3}), "points are joined by vertical bars")
+
+	assert.Equal(t, ""+
+		"4 ┤+ *\n"+
+		"3 ┤ |\n"+
+		"2 ┤*++",
+		ASCII(ASCIIOptions{Height: 3, Width: 3, Labels: &formatters.Format{}}, []float64{1, 2, nan, nan, 3, 4}, []float64{4, 4, 4, 2, 2, 2}),
+		"series are resampled to the width and NaN leaves a gap")
+
+	assert.Empty(t, ASCII(ASCIIOptions{}, []float64{nan}))
+}
+
+func TestResample(t *testing.T) {
+	assert.Equal(t, []float64{2, 4}, resample([]float64{1, 2, 3, 4}, 2))
+	assert.Equal(t, []float64{1, 2}, resample([]float64{1, 2}, 5))
+}
+
+func TestSVG(t *testing.T) {
+	chart := NewSVG(400, 200)
+	chart.Title = "BTC_USDT <1m>"
+	assert.NoError(t, chart.Candles([]float64{10, 11, 12}, []float64{12, 13, 13}, []float64{9, 10, 10}, []float64{11, 12, 10}))
+	assert.ErrorIs(t, chart.Candles([]float64{1}, nil, nil, nil), himath.ErrLengthMismatch)
+
+	fast, slow := []float64{10, 12, 11}, []float64{nan, 11, 11.5}
+	chart.Line("fast", "", fast)
+	chart.Line("slow", "#000000", slow)
+	events, err := himath.CrossLines(fast, slow, himath.CrossOptions{})
+	assert.NoError(t, err)
+	chart.Markers(events)
+
+	var buf bytes.Buffer
+	assert.NoError(t, chart.Render(&buf))
+	svg := buf.String()
+
+	assert.True(t, strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg" width="400" height="200"`))
+	assert.Contains(t, svg, "BTC_USDT &lt;1m&gt;")
+	assert.Equal(t, 3, strings.Count(svg, `<rect x=`), "candle bodies")
+	upMarkers := 0
+	for _, event := range events {
+		if event.Direction == himath.CrossUp {
+			upMarkers++
+		}
+	}
+	assert.Equal(t, 2+upMarkers, strings.Count(svg, `fill="`+upColor+`"/>`), "up candles and markers")
+	assert.Equal(t, 2, strings.Count(svg, "<polyline"))
+	assert.Contains(t, svg, `stroke="`+palette[0]+`"`)
+	assert.Equal(t, len(events), strings.Count(svg, "<path"))
+
+	decoder := xml.NewDecoder(&buf)
+	for {
+		_, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		assert.NoError(t, err, "well-formed xml")
+		if err != nil {
+			break
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "chart.svg")
+	assert.NoError(t, chart.WriteFile(path))
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, svg, string(content))
+}
+
+func TestSVGEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, NewSVG(0, 0).Render(&buf))
+	assert.Contains(t, buf.String(), `width="800" height="400"`)
+}
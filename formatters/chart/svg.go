@@ -0,0 +1,207 @@
+package chart
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/AlexanderKolesnkov/golang-utils-stuff/formatters"
+	"github.com/AlexanderKolesnkov/golang-utils-stuff/himath"
+	"html"
+	"io"
+	"math"
+	"os"
+	"strconv"
+)
+
+const (
+	upColor   = "#26a69a"
+	downColor = "#ef5350"
+	margin    = 10.0
+	axisWidth = 70.0 // space for price labels on the right
+	ticks     = 5
+)
+
+// palette of lines added without color
+var palette = []string{"#2962ff", "#ff6d00", "#ab47bc", "#00897b", "#f9a825"}
+
+type svgLine struct {
+	name   string
+	color  string
+	values []float64
+}
+
+// SVG chart of candlesticks with overlay lines and cross markers sharing the price axis,
+// the x axis is the index of the values
+type SVG struct {
+	Width  int
+	Height int
+	Title  string
+	// Labels format of the price labels, two decimals in LocaleEN by default
+	Labels *formatters.Format
+
+	open, high, low, closing []float64
+	lines                    []svgLine
+	markers                  []himath.CrossEvent
+}
+
+// NewSVG returns empty chart of the size in pixels
+func NewSVG(width, height int) *SVG {
+	return &SVG{Width: width, Height: height}
+}
+
+// Candles sets candlesticks drawn from the columns
+func (c *SVG) Candles(open, high, low, closing []float64) error {
+	if len(open) != len(high) || len(open) != len(low) || len(open) != len(closing) {
+		return himath.ErrLengthMismatch
+	}
+	c.open, c.high, c.low, c.closing = open, high, low, closing
+	return nil
+}
+
+// Line adds overlay line, e.g. moving average; NaN values leave gaps. Empty color takes the next of the palette.
+func (c *SVG) Line(name, color string, values []float64) {
+	if color == "" {
+		color = palette[len(c.lines)%len(palette)]
+	}
+	c.lines = append(c.lines, svgLine{name: name, color: color, values: values})
+}
+
+// Markers adds cross events drawn at their Position and Value: triangles up for CrossUp and down for CrossDown
+func (c *SVG) Markers(events []himath.CrossEvent) {
+	c.markers = append(c.markers, events...)
+}
+
+// length returns count of points on the x axis
+func (c *SVG) length() int {
+	n := len(c.closing)
+	for _, line := range c.lines {
+		n = max(n, len(line.values))
+	}
+	for _, event := range c.markers {
+		n = max(n, int(math.Ceil(event.Position))+1)
+	}
+	return n
+}
+
+// Render writes standalone SVG document
+func (c *SVG) Render(w io.Writer) error {
+	width, height := float64(c.Width), float64(c.Height)
+	if width <= 0 {
+		width = 800
+	}
+	if height <= 0 {
+		height = 400
+	}
+	labels := formatters.Format{Locale: formatters.LocaleEN, Precision: 2}
+	if c.Labels != nil {
+		labels = *c.Labels
+	}
+
+	series := [][]float64{c.high, c.low}
+	for _, line := range c.lines {
+		series = append(series, line.values)
+	}
+	markerValues := make([]float64, len(c.markers))
+	for i, event := range c.markers {
+		markerValues[i] = event.Value
+	}
+	series = append(series, markerValues)
+	lo, hi, ok := bounds(series...)
+	if !ok {
+		lo, hi = 0, 1
+	}
+	if hi == lo {
+		lo, hi = lo-1, hi+1
+	}
+	pad := (hi - lo) * 0.05
+	lo, hi = lo-pad, hi+pad
+
+	top := margin
+	if c.Title != "" {
+		top += 20
+	}
+	plotWidth, plotHeight := width-margin-axisWidth, height-top-margin
+	n := max(c.length(), 1)
+	step := plotWidth / float64(n)
+	x := func(position float64) float64 { return margin + (position+0.5)*step }
+	y := func(v float64) float64 { return top + (hi-v)/(hi-lo)*plotHeight }
+
+	b := bufio.NewWriter(w)
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" width="%g" height="%g" viewBox="0 0 %g %g" font-family="sans-serif" font-size="11">`+"\n", width, height, width, height)
+	fmt.Fprintf(b, `<rect width="100%%" height="100%%" fill="#ffffff"/>`+"\n")
+	if c.Title != "" {
+		fmt.Fprintf(b, `<text x="%g" y="%g" font-size="14">%s</text>`+"\n", margin, margin+12, html.EscapeString(c.Title))
+	}
+
+	for i := 0; i < ticks; i++ {
+		v := lo + (hi-lo)*float64(i)/float64(ticks-1)
+		fmt.Fprintf(b, `<line x1="%.2f" y1="%.2f" x2="%.2f" y2="%.2f" stroke="#e0e0e0"/>`+"\n", margin, y(v), margin+plotWidth, y(v))
+		fmt.Fprintf(b, `<text x="%.2f" y="%.2f" dominant-baseline="middle">%s</text>`+"\n", margin+plotWidth+5, y(v), html.EscapeString(labels.Format(v)))
+	}
+
+	body := math.Max(step*0.6, 1)
+	for i := range c.closing {
+		o, h, l, cl := c.open[i], c.high[i], c.low[i], c.closing[i]
+		if math.IsNaN(o) || math.IsNaN(h) || math.IsNaN(l) || math.IsNaN(cl) {
+			continue
+		}
+		color := upColor
+		if cl < o {
+			color = downColor
+		}
+		fmt.Fprintf(b, `<line x1="%.2f" y1="%.2f" x2="%.2f" y2="%.2f" stroke="%s"/>`+"\n", x(float64(i)), y(h), x(float64(i)), y(l), color)
+		fmt.Fprintf(b, `<rect x="%.2f" y="%.2f" width="%.2f" height="%.2f" fill="%s"/>`+"\n",
+			x(float64(i))-body/2, y(math.Max(o, cl)), body, math.Max(math.Abs(y(o)-y(cl)), 1), color)
+	}
+
+	for i, line := range c.lines {
+		color := html.EscapeString(line.color)
+		// points are appended into one buffer, concatenating strings is quadratic on long overlays
+		var points []byte
+		flush := func() {
+			if len(points) > 0 {
+				fmt.Fprintf(b, `<polyline points="%s" fill="none" stroke="%s" stroke-width="1.5"/>`+"\n", points[1:], color)
+				points = points[:0]
+			}
+		}
+		for p, v := range line.values {
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				flush()
+				continue
+			}
+			points = append(points, ' ')
+			points = strconv.AppendFloat(points, x(float64(p)), 'f', 2, 64)
+			points = append(points, ',')
+			points = strconv.AppendFloat(points, y(v), 'f', 2, 64)
+		}
+		flush()
+
+		legendY := top + 12 + float64(i)*14
+		fmt.Fprintf(b, `<text x="%g" y="%.2f" fill="%s">%s</text>`+"\n", margin+4, legendY, color, html.EscapeString(line.name))
+	}
+
+	for _, event := range c.markers {
+		mx, my := x(event.Position), y(event.Value)
+		if event.Direction == himath.CrossDown {
+			fmt.Fprintf(b, `<path d="M%.2f %.2f l-5 -9 h10 z" fill="%s"/>`+"\n", mx, my, downColor)
+			continue
+		}
+		fmt.Fprintf(b, `<path d="M%.2f %.2f l-5 9 h10 z" fill="%s"/>`+"\n", mx, my, upColor)
+	}
+
+	fmt.Fprintln(b, "</svg>")
+	return b.Flush()
+}
+
+// WriteFile renders the chart into the file at path, e.g. chart.svg
+func (c *SVG) WriteFile(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err := c.Render(file); err != nil {
+		return err
+	}
+	return file.Close()
+}
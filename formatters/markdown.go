@@ -1,14 +1,7 @@
 package formatters
 
-import (
-	"fmt"
-	"math"
-)
-
+// FloatMarkDown formats number with "," decimal separator and precision digits of the fraction, 1.25 is "1,25".
+// The comma keeps the number free of "." reserved by Telegram MarkdownV2.
 func FloatMarkDown(number, precision float64) string {
-	return fmt.Sprintf(
-		"%.0f,%0.f",
-		number,
-		math.Abs((math.Trunc(math.Abs(number))-math.Abs(number))*math.Pow(10, precision)),
-	)
+	return Format{Locale: Locale{Decimal: ","}, Precision: int(precision)}.Format(number)
 }
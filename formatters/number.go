@@ -0,0 +1,187 @@
+package formatters
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Locale separators of formatted numbers
+type Locale struct {
+	Decimal   string
+	Thousands string // empty disables grouping
+}
+
+var (
+	LocaleEN = Locale{Decimal: ".", Thousands: ","}
+	LocaleRU = Locale{Decimal: ",", Thousands: " "}
+	LocaleDE = Locale{Decimal: ",", Thousands: "."}
+	LocaleCH = Locale{Decimal: ".", Thousands: "'"}
+)
+
+// Style what the number means
+type Style int
+
+const (
+	// Decimal plain number
+	Decimal Style = iota
+	// Percent fraction shown in percent, 0.05 is "5%"
+	Percent
+	// Currency amount with Format.Currency symbol
+	Currency
+)
+
+// compactUnits suffixes of Format.Compact by powers of thousand
+var compactUnits = []string{"", "K", "M", "B", "T"}
+
+// Format number formatting options, the zero value formats integers with "." decimal separator
+type Format struct {
+	Locale Locale
+	// Precision digits after the decimal separator
+	Precision int
+	// Significant digits to keep instead of Precision when positive, 0.0012345 with 3 is "0.00123"
+	Significant int
+	// TrimZeros drops trailing zeros of the fraction, 1.50 becomes 1.5
+	TrimZeros bool
+	// Compact shortens thousands to K, M, B and T: 1234 is "1.2K" with precision 1
+	Compact bool
+	Style   Style
+	// Currency symbol of the Currency style
+	Currency string
+	// CurrencyAfter puts the symbol after the amount separated by a space: "100 USDT" instead of "$100"
+	CurrencyAfter bool
+	// Sign shows "+" for positive numbers, e.g. for pnl
+	Sign bool
+}
+
+// Format returns the number formatted by the options. Negative numbers start with "-" before the currency symbol,
+// numbers rounding to zero have no sign. NaN and infinities are "NaN", "∞" and "-∞".
+func (f Format) Format(number float64) string {
+	if f.Style == Percent {
+		number *= 100
+	}
+
+	var body string
+	negative := math.Signbit(number)
+	switch {
+	case math.IsNaN(number):
+		body, negative = "NaN", false
+	case math.IsInf(number, 0):
+		body = "∞"
+	default:
+		var zero bool
+		body, zero = f.digits(math.Abs(number))
+		negative = negative && !zero
+	}
+
+	switch f.Style {
+	case Percent:
+		body += "%"
+	case Currency:
+		if f.CurrencyAfter {
+			body += " " + f.Currency
+		} else {
+			body = f.Currency + body
+		}
+	}
+
+	switch {
+	case negative:
+		return "-" + body
+	case f.Sign && body != "NaN":
+		return "+" + body
+	}
+	return body
+}
+
+// digits returns the absolute value rounded and grouped with the compact suffix
+// and whether it rounds to zero
+func (f Format) digits(abs float64) (string, bool) {
+	unit := 0
+	if f.Compact {
+		for unit < len(compactUnits)-1 && abs >= 1000 {
+			abs /= 1000
+			unit++
+		}
+	}
+
+	rounded := f.round(abs)
+	// rounding can reach the next unit: 999.96K with precision 1 is 1.0M
+	if f.Compact && unit < len(compactUnits)-1 {
+		if value, _ := strconv.ParseFloat(rounded, 64); value >= 1000 {
+			abs /= 1000
+			unit++
+			rounded = f.round(abs)
+		}
+	}
+
+	zero := strings.Trim(rounded, "0.") == ""
+	integer, fraction, _ := strings.Cut(rounded, ".")
+	if f.TrimZeros {
+		fraction = strings.TrimRight(fraction, "0")
+	}
+
+	body := group(integer, f.Locale.Thousands)
+	if fraction != "" {
+		decimal := f.Locale.Decimal
+		if decimal == "" {
+			decimal = "."
+		}
+		body += decimal + fraction
+	}
+	return body + compactUnits[unit], zero
+}
+
+// round returns the non-negative value rounded by Precision or Significant with "." separator,
+// exact halves are rounded to even like strconv does
+func (f Format) round(abs float64) string {
+	if f.Significant <= 0 {
+		return strconv.FormatFloat(abs, 'f', max(f.Precision, 0), 64)
+	}
+
+	// scientific form rounds to the significant digits and gives the exponent after rounding
+	scientific := strconv.FormatFloat(abs, 'e', f.Significant-1, 64)
+	exponent, _ := strconv.Atoi(scientific[strings.IndexByte(scientific, 'e')+1:])
+	value, _ := strconv.ParseFloat(scientific, 64)
+	return strconv.FormatFloat(value, 'f', max(f.Significant-1-exponent, 0), 64)
+}
+
+// group inserts separator between thousands of the integer digits
+func group(integer, separator string) string {
+	if separator == "" || len(integer) <= 3 {
+		return integer
+	}
+
+	var b strings.Builder
+	head := len(integer) % 3
+	if head > 0 {
+		b.WriteString(integer[:head])
+	}
+	for i := head; i < len(integer); i += 3 {
+		if b.Len() > 0 {
+			b.WriteString(separator)
+		}
+		b.WriteString(integer[i : i+3])
+	}
+	return b.String()
+}
+
+// FormatNumber formats number with the precision and separators of the locale
+func FormatNumber(number float64, precision int, locale Locale) string {
+	return Format{Locale: locale, Precision: precision}.Format(number)
+}
+
+// FormatPercent formats fraction in percent with the precision, 0.1234 with 1 is "12.3%" in LocaleEN
+func FormatPercent(fraction float64, precision int, locale Locale) string {
+	return Format{Locale: locale, Precision: precision, Style: Percent}.Format(fraction)
+}
+
+// FormatCurrency formats amount with two decimals and the currency symbol before it, "$1,234.50" in LocaleEN
+func FormatCurrency(amount float64, symbol string, locale Locale) string {
+	return Format{Locale: locale, Precision: 2, Style: Currency, Currency: symbol}.Format(amount)
+}
+
+// FormatCompact formats number in compact notation with one decimal without trailing zero, 1234567 is "1.2M"
+func FormatCompact(number float64, locale Locale) string {
+	return Format{Locale: locale, Precision: 1, TrimZeros: true, Compact: true}.Format(number)
+}
@@ -0,0 +1,74 @@
+package formatters
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestFloatMarkDown(t *testing.T) {
+	tests := []struct {
+		number    float64
+		precision float64
+		want      string
+	}{
+		{number: 1.25, precision: 2, want: "1,25"},
+		{number: 0.05, precision: 2, want: "0,05"},
+		{number: -0.5, precision: 1, want: "-0,5"},
+		{number: -12.345, precision: 2, want: "-12,35"},
+		{number: 1.7, precision: 1, want: "1,7"},
+		{number: 1.96, precision: 1, want: "2,0"},
+		{number: 12345, precision: 0, want: "12345"},
+		{number: -0.001, precision: 2, want: "0,00"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, FloatMarkDown(tt.number, tt.precision), tt.number)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		number float64
+		want   string
+	}{
+		{name: "zero value", format: Format{}, number: 1234.6, want: "1235"},
+		{name: "thousands", format: Format{Locale: LocaleEN, Precision: 2}, number: 1234567.891, want: "1,234,567.89"},
+		{name: "russian", format: Format{Locale: LocaleRU, Precision: 2}, number: -1234567.891, want: "-1 234 567,89"},
+		{name: "german", format: Format{Locale: LocaleDE, Precision: 1}, number: 1234.56, want: "1.234,6"},
+		{name: "swiss", format: Format{Locale: LocaleCH}, number: 1000000, want: "1'000'000"},
+		{name: "three digits", format: Format{Locale: LocaleEN}, number: 999, want: "999"},
+		{name: "small negative", format: Format{Locale: LocaleEN, Precision: 3}, number: -0.05, want: "-0.050"},
+		{name: "negative zero", format: Format{Locale: LocaleEN, Precision: 2}, number: math.Copysign(0, -1), want: "0.00"},
+		{name: "trim zeros", format: Format{Locale: LocaleEN, Precision: 4, TrimZeros: true}, number: 1.5, want: "1.5"},
+		{name: "trim integer", format: Format{Locale: LocaleEN, Precision: 2, TrimZeros: true}, number: 2, want: "2"},
+		{name: "significant small", format: Format{Locale: LocaleEN, Significant: 3}, number: 0.0012345, want: "0.00123"},
+		{name: "significant large", format: Format{Locale: LocaleEN, Significant: 3}, number: 123456, want: "123,000"},
+		{name: "significant carry", format: Format{Locale: LocaleEN, Significant: 2}, number: 9.99, want: "10"},
+		{name: "compact thousands", format: Format{Locale: LocaleEN, Precision: 1, Compact: true}, number: 1234, want: "1.2K"},
+		{name: "compact millions", format: Format{Locale: LocaleRU, Precision: 1, Compact: true}, number: -3456789, want: "-3,5M"},
+		{name: "compact carry", format: Format{Locale: LocaleEN, Precision: 1, Compact: true}, number: 999960, want: "1.0M"},
+		{name: "compact small", format: Format{Locale: LocaleEN, Precision: 2, Compact: true}, number: 12.345, want: "12.35"},
+		{name: "compact trillions", format: Format{Locale: LocaleEN, Compact: true}, number: 5e15, want: "5,000T"},
+		{name: "percent", format: Format{Locale: LocaleEN, Precision: 1, Style: Percent}, number: 0.1234, want: "12.3%"},
+		{name: "signed percent", format: Format{Locale: LocaleEN, Precision: 2, Style: Percent, Sign: true}, number: 0.05, want: "+5.00%"},
+		{name: "currency", format: Format{Locale: LocaleEN, Precision: 2, Style: Currency, Currency: "$"}, number: -1234.5, want: "-$1,234.50"},
+		{name: "currency after", format: Format{Locale: LocaleRU, Precision: 2, Style: Currency, Currency: "USDT", CurrencyAfter: true}, number: 100, want: "100,00 USDT"},
+		{name: "nan", format: Format{Sign: true}, number: math.NaN(), want: "NaN"},
+		{name: "infinity", format: Format{Style: Currency, Currency: "$"}, number: math.Inf(-1), want: "-$∞"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.format.Format(tt.number))
+		})
+	}
+}
+
+func TestShortcuts(t *testing.T) {
+	assert.Equal(t, "1,234.57", FormatNumber(1234.567, 2, LocaleEN))
+	assert.Equal(t, "12,3%", FormatPercent(0.1234, 1, LocaleRU))
+	assert.Equal(t, "€1.234,50", FormatCurrency(1234.5, "€", LocaleDE))
+	assert.Equal(t, "1.2M", FormatCompact(1234567, LocaleEN))
+	assert.Equal(t, "2K", FormatCompact(2000, LocaleEN))
+}
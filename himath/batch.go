@@ -0,0 +1,299 @@
+package himath
+
+import "math"
+
+// Batch indicators return series aligned with the input: output[i] belongs to input[i].
+// Values are NaN while the indicator has not enough data (the leading gap),
+// the length of the gap is documented for each function.
+
+// nanSeries returns a slice of n NaN values
+func nanSeries(n int) []float64 {
+	series := make([]float64, n)
+	for i := range series {
+		series[i] = math.NaN()
+	}
+	return series
+}
+
+// streamSeries runs the indicator over values and collects Value() after every update
+func streamSeries(ind Indicator, values []float64) []float64 {
+	output := make([]float64, len(values))
+	for i, v := range values {
+		ind.Update(v)
+		output[i] = ind.Value()
+	}
+	return output
+}
+
+func sameLength(series ...[]float64) bool {
+	for _, s := range series[1:] {
+		if len(s) != len(series[0]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Sma simple moving average
+//
+// Leading gap: period - 1
+func Sma(period int, values []float64) ([]float64, error) {
+	sma, err := NewSmaIndicator(period)
+	if err != nil {
+		return nil, err
+	}
+	return streamSeries(sma, values), nil
+}
+
+// Ema exponential moving average seeded with the first value
+//
+// Leading gap: period - 1
+func Ema(period int, values []float64) ([]float64, error) {
+	ema, err := NewEmaIndicator(period)
+	if err != nil {
+		return nil, err
+	}
+	return streamSeries(ema, values), nil
+}
+
+// Rsi relative strength index with Wilder smoothing
+//
+// Leading gap: period
+func Rsi(period int, values []float64) ([]float64, error) {
+	rsi, err := NewRsiIndicator(period)
+	if err != nil {
+		return nil, err
+	}
+	return streamSeries(rsi, values), nil
+}
+
+// Macd returns MACD line, signal line and histogram
+//
+// Leading gap: max(fast, slow, signal) - 1
+func Macd(fast, slow, signal int, values []float64) (macd, signalLine, histogram []float64, err error) {
+	ind, err := NewMacdIndicator(fast, slow, signal)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	macd = make([]float64, len(values))
+	signalLine = make([]float64, len(values))
+	histogram = make([]float64, len(values))
+	for i, v := range values {
+		ind.Update(v)
+		macd[i] = ind.Value()
+		signalLine[i] = ind.Signal()
+		histogram[i] = ind.Histogram()
+	}
+	return macd, signalLine, histogram, nil
+}
+
+// BollingerBands returns upper, middle and lower bands
+//
+// Leading gap: period - 1
+func BollingerBands(period int, multiplier float64, values []float64) (upper, middle, lower []float64, err error) {
+	ind, err := NewBollingerBandsIndicator(period, multiplier)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	upper = make([]float64, len(values))
+	middle = make([]float64, len(values))
+	lower = make([]float64, len(values))
+	for i, v := range values {
+		ind.Update(v)
+		upper[i] = ind.Upper()
+		middle[i] = ind.Value()
+		lower[i] = ind.Lower()
+	}
+	return upper, middle, lower, nil
+}
+
+// Atr average true range with Wilder smoothing
+//
+// Leading gap: period - 1
+func Atr(period int, high, low, closing []float64) ([]float64, error) {
+	if !sameLength(high, low, closing) {
+		return nil, ErrLengthMismatch
+	}
+	atr, err := NewAtrIndicator(period)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]float64, len(closing))
+	for i := range closing {
+		atr.UpdateCandle(high[i], low[i], closing[i])
+		output[i] = atr.Value()
+	}
+	return output, nil
+}
+
+// KeltnerChannels returns upper, middle and lower channels
+//
+// Formula: Middle = EMA(period), Upper/Lower = Middle ± multiplier * ATR(period)
+//
+// Leading gap: period - 1
+func KeltnerChannels(period int, multiplier float64, high, low, closing []float64) (upper, middle, lower []float64, err error) {
+	atr, err := Atr(period, high, low, closing)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	middle, err = Ema(period, closing)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	upper = make([]float64, len(closing))
+	lower = make([]float64, len(closing))
+	for i := range closing {
+		upper[i] = middle[i] + multiplier*atr[i]
+		lower[i] = middle[i] - multiplier*atr[i]
+	}
+	return upper, middle, lower, nil
+}
+
+// Adx average directional index with +DI and -DI (Wilder)
+//
+// Formula: DX = 100 * |+DI - -DI| / (+DI + -DI), ADX = RMA(period) of DX
+//
+// Leading gap: period for +DI/-DI, 2 * period - 1 for ADX
+func Adx(period int, high, low, closing []float64) (adx, plusDI, minusDI []float64, err error) {
+	if period <= 0 {
+		return nil, nil, nil, ErrInvalidPeriod
+	}
+	if !sameLength(high, low, closing) {
+		return nil, nil, nil, ErrLengthMismatch
+	}
+
+	n := len(closing)
+	adx, plusDI, minusDI = nanSeries(n), nanSeries(n), nanSeries(n)
+
+	p := float64(period)
+	var trSum, plusSum, minusSum, dxSum float64
+	for i := 1; i < n; i++ {
+		up := high[i] - high[i-1]
+		down := low[i-1] - low[i]
+
+		plusDM, minusDM := 0.0, 0.0
+		if up > down && up > 0 {
+			plusDM = up
+		}
+		if down > up && down > 0 {
+			minusDM = down
+		}
+		tr := math.Max(high[i]-low[i], math.Max(math.Abs(high[i]-closing[i-1]), math.Abs(low[i]-closing[i-1])))
+
+		if i <= period {
+			trSum += tr
+			plusSum += plusDM
+			minusSum += minusDM
+		} else {
+			trSum = trSum - trSum/p + tr
+			plusSum = plusSum - plusSum/p + plusDM
+			minusSum = minusSum - minusSum/p + minusDM
+		}
+		if i < period {
+			continue
+		}
+
+		plusDI[i], minusDI[i] = 0, 0
+		if trSum != 0 {
+			plusDI[i] = 100 * plusSum / trSum
+			minusDI[i] = 100 * minusSum / trSum
+		}
+
+		dx := 0.0
+		if sum := plusDI[i] + minusDI[i]; sum != 0 {
+			dx = 100 * math.Abs(plusDI[i]-minusDI[i]) / sum
+		}
+
+		switch {
+		case i < 2*period-1:
+			dxSum += dx
+		case i == 2*period-1:
+			adx[i] = (dxSum + dx) / p
+		default:
+			adx[i] = (adx[i-1]*(p-1) + dx) / p
+		}
+	}
+	return adx, plusDI, minusDI, nil
+}
+
+// Obv on-balance volume starting from zero
+//
+// Leading gap: none
+func Obv(closing, volume []float64) ([]float64, error) {
+	if !sameLength(closing, volume) {
+		return nil, ErrLengthMismatch
+	}
+
+	obv := make([]float64, len(closing))
+	for i := 1; i < len(closing); i++ {
+		obv[i] = obv[i-1]
+		if closing[i] > closing[i-1] {
+			obv[i] += volume[i]
+		} else if closing[i] < closing[i-1] {
+			obv[i] -= volume[i]
+		}
+	}
+	return obv, nil
+}
+
+// Vwap volume weighted average price over the rolling period, period 0 accumulates from the first value
+//
+// Formula: sum(price * volume) / sum(volume)
+//
+// Leading gap: period - 1, NaN when the volume of the window is zero
+func Vwap(period int, price, volume []float64) ([]float64, error) {
+	if period < 0 {
+		return nil, ErrInvalidPeriod
+	}
+	if !sameLength(price, volume) {
+		return nil, ErrLengthMismatch
+	}
+
+	vwap := nanSeries(len(price))
+	var pv, v float64
+	for i := range price {
+		pv += price[i] * volume[i]
+		v += volume[i]
+		if period > 0 && i >= period {
+			pv -= price[i-period] * volume[i-period]
+			v -= volume[i-period]
+		}
+		if (period == 0 || i >= period-1) && v != 0 {
+			vwap[i] = pv / v
+		}
+	}
+	return vwap, nil
+}
+
+// DonchianChannels returns the highest high, the middle and the lowest low over the period
+//
+// Leading gap: period - 1
+func DonchianChannels(period int, high, low []float64) (upper, middle, lower []float64, err error) {
+	if period <= 0 {
+		return nil, nil, nil, ErrInvalidPeriod
+	}
+	if !sameLength(high, low) {
+		return nil, nil, nil, ErrLengthMismatch
+	}
+
+	n := len(high)
+	upper, middle, lower = nanSeries(n), nanSeries(n), nanSeries(n)
+
+	highs, lows := newWindow(period), newWindow(period)
+	for i := range high {
+		highs.push(high[i])
+		lows.push(low[i])
+		if !highs.full() {
+			continue
+		}
+
+		_, upper[i] = highs.minMax()
+		lower[i], _ = lows.minMax()
+		middle[i] = (upper[i] + lower[i]) / 2
+	}
+	return upper, middle, lower, nil
+}
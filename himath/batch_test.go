@@ -0,0 +1,173 @@
+package himath
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+var (
+	nan = math.NaN()
+
+	// reference candles, expected values are calculated by hand with Wilder smoothing
+	refHigh    = []float64{10, 11, 12, 11.5, 13, 12.5, 14, 13.5}
+	refLow     = []float64{9, 9.5, 10.5, 10, 11, 11.5, 12, 12}
+	refClosing = []float64{9.5, 10.5, 11.5, 10.5, 12.5, 12, 13.5, 12.5}
+	refVolume  = []float64{100, 200, 150, 120, 300, 250, 400, 180}
+)
+
+// assertSeries compares series element by element, NaN is expected only where want is NaN
+func assertSeries(t *testing.T, want, got []float64) {
+	t.Helper()
+	if !assert.Len(t, got, len(want)) {
+		return
+	}
+	for i := range want {
+		if math.IsNaN(want[i]) {
+			assert.True(t, math.IsNaN(got[i]), "index %d: want NaN, got %v", i, got[i])
+			continue
+		}
+		assert.InDelta(t, want[i], got[i], 1e-9, "index %d", i)
+	}
+}
+
+func TestSingleSeriesIndicators(t *testing.T) {
+	tests := []struct {
+		name    string
+		fn      func(period int, values []float64) ([]float64, error)
+		period  int
+		values  []float64
+		want    []float64
+		wantErr error
+	}{
+		{
+			name:   "SMA",
+			fn:     Sma,
+			period: 3,
+			values: []float64{1, 2, 3, 4, 5, 6},
+			want:   []float64{nan, nan, 2, 3, 4, 5},
+		},
+		{
+			name:   "SMA shorter than period",
+			fn:     Sma,
+			period: 3,
+			values: []float64{1, 2},
+			want:   []float64{nan, nan},
+		},
+		{
+			name:   "EMA",
+			fn:     Ema,
+			period: 3,
+			values: []float64{1, 2, 3, 4},
+			want:   []float64{nan, nan, 2.25, 3.125},
+		},
+		{
+			name:   "EMA reference",
+			fn:     Ema,
+			period: 3,
+			values: refClosing,
+			want:   []float64{nan, nan, 10.75, 10.625, 11.5625, 11.78125, 12.640625, 12.5703125},
+		},
+		{
+			name:   "RSI",
+			fn:     Rsi,
+			period: 3,
+			values: refClosing,
+			want:   []float64{nan, nan, nan, 66.66666666666666, 83.33333333333333, 70.17543859649123, 82.56410256410257, 58.33333333333332},
+		},
+		{
+			name:   "RSI only gains",
+			fn:     Rsi,
+			period: 2,
+			values: []float64{1, 2, 3, 4},
+			want:   []float64{nan, nan, 100, 100},
+		},
+		{
+			name:    "invalid period",
+			fn:      Sma,
+			period:  0,
+			values:  []float64{1},
+			wantErr: ErrInvalidPeriod,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.fn(tt.period, tt.values)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assertSeries(t, tt.want, got)
+		})
+	}
+}
+
+func TestMacd(t *testing.T) {
+	macd, signal, histogram, err := Macd(2, 3, 2, []float64{1, 2, 3, 4})
+	assert.NoError(t, err)
+
+	// EMA2: 1, 1.6667, 2.5556, 3.5185; EMA3: 1, 1.5, 2.25, 3.125
+	assertSeries(t, []float64{nan, nan, 0.3055555555555556, 0.3935185185185186}, macd)
+	assertSeries(t, []float64{nan, nan, 0.24074074074074076, 0.3425925925925926}, signal)
+	assertSeries(t, []float64{nan, nan, 0.06481481481481483, 0.05092592592592593}, histogram)
+}
+
+func TestBollingerBands(t *testing.T) {
+	upper, middle, lower, err := BollingerBands(3, 2, refClosing)
+	assert.NoError(t, err)
+
+	assertSeries(t, []float64{nan, nan, 12.132993161855453, 11.776142374915397, 13.132993161855453, 13.366339837864261, 13.913885795591312, 13.913885795591312}, upper)
+	assertSeries(t, []float64{nan, nan, 10.5, 10.833333333333334, 11.5, 11.666666666666666, 12.666666666666666, 12.666666666666666}, middle)
+	assertSeries(t, []float64{nan, nan, 8.867006838144547, 9.890524291751271, 9.867006838144547, 9.96699349546907, 11.41944753774202, 11.41944753774202}, lower)
+}
+
+func TestCandleIndicators(t *testing.T) {
+	atr, err := Atr(3, refHigh, refLow, refClosing)
+	assert.NoError(t, err)
+	assertSeries(t, []float64{nan, nan, 1.3333333333333333, 1.3888888888888886, 1.7592592592592589, 1.5061728395061724, 1.670781893004115, 1.6138545953360766}, atr)
+
+	upper, middle, lower, err := KeltnerChannels(3, 2, refHigh, refLow, refClosing)
+	assert.NoError(t, err)
+	assertSeries(t, []float64{nan, nan, 13.416666666666666, 13.402777777777777, 15.081018518518517, 14.793595679012345, 15.98218878600823, 15.798021690672153}, upper)
+	assertSeries(t, []float64{nan, nan, 10.75, 10.625, 11.5625, 11.78125, 12.640625, 12.5703125}, middle)
+	assertSeries(t, []float64{nan, nan, 8.083333333333334, 7.847222222222223, 8.043981481481483, 8.768904320987655, 9.29906121399177, 9.342603309327847}, lower)
+
+	adx, plusDI, minusDI, err := Adx(3, refHigh, refLow, refClosing)
+	assert.NoError(t, err)
+	assertSeries(t, []float64{nan, nan, nan, nan, nan, 72.63157894736842, 78.35735836406303, 82.17454464185943}, adx)
+	assertSeries(t, []float64{nan, nan, nan, 44.44444444444444, 51.51515151515152, 40.476190476190474, 53.985507246376805, 37.48427672955974}, plusDI)
+	assertSeries(t, []float64{nan, nan, nan, 11.11111111111111, 6.060606060606061, 4.761904761904762, 2.898550724637682, 2.0125786163522017}, minusDI)
+
+	upper, middle, lower, err = DonchianChannels(3, refHigh, refLow)
+	assert.NoError(t, err)
+	assertSeries(t, []float64{nan, nan, 12, 12, 13, 13, 14, 14}, upper)
+	assertSeries(t, []float64{nan, nan, 10.5, 10.75, 11.5, 11.5, 12.5, 12.75}, middle)
+	assertSeries(t, []float64{nan, nan, 9, 9.5, 10, 10, 11, 11.5}, lower)
+
+	_, err = Atr(3, refHigh, refLow[1:], refClosing)
+	assert.ErrorIs(t, err, ErrLengthMismatch)
+	_, _, _, err = Adx(3, refHigh[1:], refLow, refClosing)
+	assert.ErrorIs(t, err, ErrLengthMismatch)
+}
+
+func TestVolumeIndicators(t *testing.T) {
+	obv, err := Obv([]float64{10, 11, 10, 10, 12}, []float64{1, 2, 3, 4, 5})
+	assert.NoError(t, err)
+	assertSeries(t, []float64{0, 2, -1, -1, 4}, obv)
+
+	vwap, err := Vwap(3, refClosing, refVolume)
+	assert.NoError(t, err)
+	assertSeries(t, []float64{nan, nan, 10.61111111111111, 10.819148936170214, 11.81578947368421, 11.955223880597014, 12.789473684210526, 12.831325301204819}, vwap)
+
+	cumulative, err := Vwap(0, []float64{10, 20, 30}, []float64{1, 1, 2})
+	assert.NoError(t, err)
+	assertSeries(t, []float64{10, 15, 22.5}, cumulative)
+
+	zeroVolume, err := Vwap(2, []float64{10, 20, 30}, []float64{0, 0, 1})
+	assert.NoError(t, err)
+	assertSeries(t, []float64{nan, nan, 30}, zeroVolume)
+
+	_, err = Obv([]float64{1}, nil)
+	assert.ErrorIs(t, err, ErrLengthMismatch)
+}
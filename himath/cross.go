@@ -0,0 +1,205 @@
+package himath
+
+import "math"
+
+// CrossKind type of crossing event
+type CrossKind int
+
+const (
+	// GoldenCross the first line crosses the second one from below
+	GoldenCross CrossKind = iota
+	// DeathCross the first line crosses the second one from above
+	DeathCross
+	// ThresholdEntry the series crosses the level into the zone
+	ThresholdEntry
+	// ThresholdExit the series crosses the level out of the zone
+	ThresholdExit
+)
+
+// CrossDirection direction of the first line relative to the second one
+type CrossDirection int
+
+const (
+	CrossUp CrossDirection = iota + 1
+	CrossDown
+)
+
+// Zone side of the threshold level that is watched, e.g. ZoneAbove 70 for overbought RSI
+type Zone int
+
+const (
+	ZoneAbove Zone = iota
+	ZoneBelow
+)
+
+// EqualityMode how points where the lines are equal are treated
+type EqualityMode int
+
+const (
+	// EqualKeepsSide touching keeps the previous side, the cross is reported on the first point beyond
+	EqualKeepsSide EqualityMode = iota
+	// EqualAsAbove touching counts as being above, like a >= b
+	EqualAsAbove
+	// EqualAsBelow touching counts as being below, like a <= b
+	EqualAsBelow
+)
+
+// CrossOptions configure crossing detection
+type CrossOptions struct {
+	Equality EqualityMode
+	// Hysteresis the difference of the lines must leave ±Hysteresis band to change the side,
+	// it suppresses whipsaws around the crossing
+	Hysteresis float64
+}
+
+// CrossEvent single crossing
+type CrossEvent struct {
+	Kind      CrossKind
+	Direction CrossDirection
+	// Index of the point where the new side is confirmed
+	Index int
+	// Position fractional index where the lines intersect by linear interpolation from the previous point
+	Position float64
+	// Value of the lines at Position
+	Value float64
+}
+
+type crossSide int
+
+const (
+	sideUnknown crossSide = iota
+	sideAbove
+	sideBelow
+)
+
+// CrossDetector finds crossings of two streams point by point
+type CrossDetector struct {
+	options CrossOptions
+
+	side      crossSide
+	index     int
+	prevIndex int
+	prevA     float64
+	prevB     float64
+	hasPrev   bool
+
+	threshold bool
+	zone      Zone
+}
+
+// NewCrossDetector returns detector of GoldenCross and DeathCross events
+func NewCrossDetector(options CrossOptions) *CrossDetector {
+	return &CrossDetector{options: options}
+}
+
+// NewThresholdDetector returns detector of ThresholdEntry and ThresholdExit events,
+// Update must be called with the level as the second value
+func NewThresholdDetector(zone Zone, options CrossOptions) *CrossDetector {
+	return &CrossDetector{options: options, threshold: true, zone: zone}
+}
+
+// nextSide returns the side of a - b difference, the previous side is kept inside hysteresis band
+func (c *CrossDetector) nextSide(diff float64) crossSide {
+	h := math.Abs(c.options.Hysteresis)
+	switch {
+	case diff > h:
+		return sideAbove
+	case diff < -h:
+		return sideBelow
+	case diff == h && c.options.Equality == EqualAsAbove:
+		return sideAbove
+	case diff == -h && c.options.Equality == EqualAsBelow:
+		return sideBelow
+	}
+	return c.side
+}
+
+// kind maps direction to the event kind of the detector
+func (c *CrossDetector) kind(direction CrossDirection) CrossKind {
+	if !c.threshold {
+		if direction == CrossUp {
+			return GoldenCross
+		}
+		return DeathCross
+	}
+
+	if (direction == CrossUp) == (c.zone == ZoneAbove) {
+		return ThresholdEntry
+	}
+	return ThresholdExit
+}
+
+// Update feeds the next pair of values and returns the event when the side has changed.
+// NaN values are skipped but still counted in Index.
+func (c *CrossDetector) Update(a, b float64) (CrossEvent, bool) {
+	index := c.index
+	c.index++
+
+	diff := a - b
+	if math.IsNaN(diff) {
+		return CrossEvent{}, false
+	}
+
+	prevIndex, prevA, prevB, hasPrev := c.prevIndex, c.prevA, c.prevB, c.hasPrev
+	c.prevIndex, c.prevA, c.prevB, c.hasPrev = index, a, b, true
+
+	side := c.nextSide(diff)
+	prevSide := c.side
+	c.side = side
+
+	if prevSide == sideUnknown || side == prevSide {
+		return CrossEvent{}, false
+	}
+
+	direction := CrossUp
+	if side == sideBelow {
+		direction = CrossDown
+	}
+
+	event := CrossEvent{
+		Kind:      c.kind(direction),
+		Direction: direction,
+		Index:     index,
+		Position:  float64(index),
+		Value:     a,
+	}
+
+	if hasPrev {
+		d0 := prevA - prevB
+		t := 1.0
+		if d0 != diff {
+			t = math.Max(0, math.Min(1, d0/(d0-diff)))
+		}
+		event.Position = float64(prevIndex) + t*float64(index-prevIndex)
+		event.Value = prevA + t*(a-prevA)
+	}
+	return event, true
+}
+
+// CrossLines returns crossings of line a over line b
+func CrossLines(a, b []float64, options CrossOptions) ([]CrossEvent, error) {
+	if len(a) != len(b) {
+		return nil, ErrLengthMismatch
+	}
+
+	detector := NewCrossDetector(options)
+	var events []CrossEvent
+	for i := range a {
+		if event, ok := detector.Update(a[i], b[i]); ok {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// CrossThreshold returns entries into and exits from the zone beyond the level
+func CrossThreshold(values []float64, level float64, zone Zone, options CrossOptions) []CrossEvent {
+	detector := NewThresholdDetector(zone, options)
+	var events []CrossEvent
+	for _, v := range values {
+		if event, ok := detector.Update(v, level); ok {
+			events = append(events, event)
+		}
+	}
+	return events
+}
@@ -0,0 +1,129 @@
+package himath
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestCrossLines(t *testing.T) {
+	line := []float64{2, 2, 2, 2, 2}
+	touching := []float64{1, 2, 3, 2, 1}
+
+	tests := []struct {
+		name    string
+		a       []float64
+		b       []float64
+		options CrossOptions
+		want    []CrossEvent
+	}{
+		{
+			name: "interpolated crossing",
+			a:    []float64{0, 10},
+			b:    []float64{5, 5},
+			want: []CrossEvent{{Kind: GoldenCross, Direction: CrossUp, Index: 1, Position: 0.5, Value: 5}},
+		},
+		{
+			name: "touch keeps side",
+			a:    touching,
+			b:    line,
+			want: []CrossEvent{
+				{Kind: GoldenCross, Direction: CrossUp, Index: 2, Position: 1, Value: 2},
+				{Kind: DeathCross, Direction: CrossDown, Index: 4, Position: 3, Value: 2},
+			},
+		},
+		{
+			name:    "touch as above",
+			a:       touching,
+			b:       line,
+			options: CrossOptions{Equality: EqualAsAbove},
+			want: []CrossEvent{
+				{Kind: GoldenCross, Direction: CrossUp, Index: 1, Position: 1, Value: 2},
+				{Kind: DeathCross, Direction: CrossDown, Index: 4, Position: 3, Value: 2},
+			},
+		},
+		{
+			name:    "touch as below",
+			a:       touching,
+			b:       line,
+			options: CrossOptions{Equality: EqualAsBelow},
+			want: []CrossEvent{
+				{Kind: GoldenCross, Direction: CrossUp, Index: 2, Position: 1, Value: 2},
+				{Kind: DeathCross, Direction: CrossDown, Index: 3, Position: 3, Value: 2},
+			},
+		},
+		{
+			name:    "hysteresis suppresses whipsaws",
+			a:       []float64{0, 1.2, 0.9, 1.1, 0.8, 1.6, 0.3},
+			b:       []float64{1, 1, 1, 1, 1, 1, 1},
+			options: CrossOptions{Hysteresis: 0.5},
+			want: []CrossEvent{
+				{Kind: GoldenCross, Direction: CrossUp, Index: 5, Position: 4.25, Value: 1},
+				{Kind: DeathCross, Direction: CrossDown, Index: 6, Position: 5 + 0.6/1.3, Value: 1},
+			},
+		},
+		{
+			name: "NaN is skipped",
+			a:    []float64{1, math.NaN(), 3},
+			b:    []float64{2, 2, 2},
+			want: []CrossEvent{{Kind: GoldenCross, Direction: CrossUp, Index: 2, Position: 1, Value: 2}},
+		},
+		{
+			name: "no crossing",
+			a:    []float64{1, 2, 3},
+			b:    []float64{0, 0, 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CrossLines(tt.a, tt.b, tt.options)
+			assert.NoError(t, err)
+			assertCrossEvents(t, tt.want, got)
+		})
+	}
+
+	_, err := CrossLines([]float64{1}, nil, CrossOptions{})
+	assert.ErrorIs(t, err, ErrLengthMismatch)
+}
+
+func TestCrossThreshold(t *testing.T) {
+	overbought := CrossThreshold([]float64{60, 72, 75, 68, 65}, 70, ZoneAbove, CrossOptions{})
+	assertCrossEvents(t, []CrossEvent{
+		{Kind: ThresholdEntry, Direction: CrossUp, Index: 1, Position: 10.0 / 12, Value: 70},
+		{Kind: ThresholdExit, Direction: CrossDown, Index: 3, Position: 2 + 5.0/7, Value: 70},
+	}, overbought)
+
+	oversold := CrossThreshold([]float64{35, 25, 28, 31}, 30, ZoneBelow, CrossOptions{})
+	assertCrossEvents(t, []CrossEvent{
+		{Kind: ThresholdEntry, Direction: CrossDown, Index: 1, Position: 0.5, Value: 30},
+		{Kind: ThresholdExit, Direction: CrossUp, Index: 3, Position: 2 + 2.0/3, Value: 30},
+	}, oversold)
+}
+
+func TestCrossDetectorStream(t *testing.T) {
+	detector := NewCrossDetector(CrossOptions{})
+
+	_, ok := detector.Update(1, 2)
+	assert.False(t, ok)
+	_, ok = detector.Update(1.5, 2)
+	assert.False(t, ok)
+
+	event, ok := detector.Update(3, 2)
+	assert.True(t, ok)
+	assert.Equal(t, GoldenCross, event.Kind)
+	assert.Equal(t, 2, event.Index)
+}
+
+func assertCrossEvents(t *testing.T, want, got []CrossEvent) {
+	t.Helper()
+	if !assert.Len(t, got, len(want)) {
+		return
+	}
+	for i := range want {
+		assert.Equal(t, want[i].Kind, got[i].Kind, "event %d", i)
+		assert.Equal(t, want[i].Direction, got[i].Direction, "event %d", i)
+		assert.Equal(t, want[i].Index, got[i].Index, "event %d", i)
+		assert.InDelta(t, want[i].Position, got[i].Position, 1e-9, "event %d", i)
+		assert.InDelta(t, want[i].Value, got[i].Value, 1e-9, "event %d", i)
+	}
+}
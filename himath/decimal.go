@@ -0,0 +1,304 @@
+package himath
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// RoundingMode how Decimal drops digits
+type RoundingMode int
+
+const (
+	// RoundHalfEven rounds to the nearest, ties to the even digit (banker's rounding)
+	RoundHalfEven RoundingMode = iota
+	// RoundHalfUp rounds to the nearest, ties away from zero
+	RoundHalfUp
+	// RoundDown rounds toward negative infinity (floor)
+	RoundDown
+	// RoundUp rounds toward positive infinity (ceil)
+	RoundUp
+)
+
+// DivisionScale digits after the point kept by decimal formulas that divide
+const DivisionScale = 16
+
+// MaxDecimalScale bound of |scale| accepted by ParseDecimal, it keeps hostile exponents
+// like "1e2147483647" from building huge coefficients
+const MaxDecimalScale = 1000
+
+// Decimal exact decimal number coef * 10^-scale. The zero value is 0.
+//
+// Decimal is immutable, every operation returns a new value.
+type Decimal struct {
+	coef  *big.Int
+	scale int32
+}
+
+// NewDecimal returns coef * 10^-scale, NewDecimal(8205, 4) is 0.8205
+func NewDecimal(coef int64, scale int32) Decimal {
+	return Decimal{coef: big.NewInt(coef), scale: scale}
+}
+
+// ParseDecimal parses exchange strings like "0.8205", "-12", "1e-5".
+// Numbers with scale beyond ±MaxDecimalScale are rejected with ErrInvalidDecimal.
+func ParseDecimal(s string) (Decimal, error) {
+	str := strings.TrimSpace(s)
+
+	exp := int64(0)
+	if i := strings.IndexAny(str, "eE"); i >= 0 {
+		var err error
+		exp, err = strconv.ParseInt(str[i+1:], 10, 32)
+		if err != nil {
+			return Decimal{}, fmt.Errorf("%w: %q", ErrInvalidDecimal, s)
+		}
+		str = str[:i]
+	}
+
+	intPart, fracPart, _ := strings.Cut(str, ".")
+	digits := intPart + fracPart
+	if digits == "" || digits == "-" || digits == "+" || strings.ContainsAny(digits[1:], "+-") {
+		return Decimal{}, fmt.Errorf("%w: %q", ErrInvalidDecimal, s)
+	}
+
+	coef, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return Decimal{}, fmt.Errorf("%w: %q", ErrInvalidDecimal, s)
+	}
+
+	scale := int64(len(fracPart)) - exp
+	if scale > MaxDecimalScale || scale < -MaxDecimalScale {
+		return Decimal{}, fmt.Errorf("%w: scale out of range: %q", ErrInvalidDecimal, s)
+	}
+	if scale < 0 {
+		coef.Mul(coef, pow10(int32(-scale)))
+		scale = 0
+	}
+	return Decimal{coef: coef, scale: int32(scale)}, nil
+}
+
+// MustParseDecimal is ParseDecimal that panics on error, for constants and tests
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// DecimalFromFloat converts float to the shortest decimal that reads back as the same float,
+// so 0.8205 becomes exactly 0.8205. NaN and Inf become zero.
+func DecimalFromFloat(f float64) Decimal {
+	d, _ := ParseDecimal(strconv.FormatFloat(f, 'g', -1, 64))
+	return d
+}
+
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// int returns coefficient, nil is treated as zero
+func (d Decimal) int() *big.Int {
+	if d.coef == nil {
+		return new(big.Int)
+	}
+	return d.coef
+}
+
+// rescale returns coefficient of d at the bigger scale
+func (d Decimal) rescale(scale int32) *big.Int {
+	if scale == d.scale {
+		return new(big.Int).Set(d.int())
+	}
+	return new(big.Int).Mul(d.int(), pow10(scale-d.scale))
+}
+
+// align returns coefficients of both decimals at the common scale
+func align(a, b Decimal) (*big.Int, *big.Int, int32) {
+	scale := max(a.scale, b.scale)
+	return a.rescale(scale), b.rescale(scale), scale
+}
+
+// roundQuotient returns num / den rounded to integer by mode
+func roundQuotient(num, den *big.Int, mode RoundingMode) *big.Int {
+	if den.Sign() < 0 {
+		num, den = new(big.Int).Neg(num), new(big.Int).Neg(den)
+	}
+
+	q, r := new(big.Int).QuoRem(num, den, new(big.Int))
+	if r.Sign() == 0 {
+		return q
+	}
+
+	away := false
+	switch mode {
+	case RoundDown:
+		away = num.Sign() < 0
+	case RoundUp:
+		away = num.Sign() > 0
+	case RoundHalfUp, RoundHalfEven:
+		half := new(big.Int).Abs(r)
+		half.Lsh(half, 1)
+		switch half.Cmp(den) {
+		case 1:
+			away = true
+		case 0:
+			away = mode == RoundHalfUp || q.Bit(0) == 1
+		}
+	}
+
+	if away {
+		q.Add(q, big.NewInt(int64(num.Sign())))
+	}
+	return q
+}
+
+func (d Decimal) Add(other Decimal) Decimal {
+	a, b, scale := align(d, other)
+	return Decimal{coef: a.Add(a, b), scale: scale}
+}
+
+func (d Decimal) Sub(other Decimal) Decimal {
+	a, b, scale := align(d, other)
+	return Decimal{coef: a.Sub(a, b), scale: scale}
+}
+
+func (d Decimal) Mul(other Decimal) Decimal {
+	return Decimal{coef: new(big.Int).Mul(d.int(), other.int()), scale: d.scale + other.scale}
+}
+
+// Div returns d / other with scale digits after the point rounded by mode
+func (d Decimal) Div(other Decimal, scale int32, mode RoundingMode) (Decimal, error) {
+	if other.IsZero() {
+		return Decimal{}, ErrDivisionByZero
+	}
+
+	// d / other = (a / b) * 10^(other.scale - d.scale), the result coefficient is shifted by scale
+	num, den := new(big.Int).Set(d.int()), new(big.Int).Set(other.int())
+	if shift := scale + other.scale - d.scale; shift >= 0 {
+		num.Mul(num, pow10(shift))
+	} else {
+		den.Mul(den, pow10(-shift))
+	}
+	return Decimal{coef: roundQuotient(num, den, mode), scale: scale}, nil
+}
+
+func (d Decimal) Neg() Decimal {
+	return Decimal{coef: new(big.Int).Neg(d.int()), scale: d.scale}
+}
+
+func (d Decimal) Abs() Decimal {
+	return Decimal{coef: new(big.Int).Abs(d.int()), scale: d.scale}
+}
+
+// Sign returns -1, 0 or 1
+func (d Decimal) Sign() int {
+	return d.int().Sign()
+}
+
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// Cmp returns -1 if d < other, 0 if d == other and 1 if d > other
+func (d Decimal) Cmp(other Decimal) int {
+	a, b, _ := align(d, other)
+	return a.Cmp(b)
+}
+
+// Equal compares values, 1.50 is equal to 1.5
+func (d Decimal) Equal(other Decimal) bool {
+	return d.Cmp(other) == 0
+}
+
+func (d Decimal) LessThan(other Decimal) bool {
+	return d.Cmp(other) < 0
+}
+
+func (d Decimal) GreaterThan(other Decimal) bool {
+	return d.Cmp(other) > 0
+}
+
+// Scale returns count of digits after the point
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+// Round returns d with scale digits after the point, scale bigger than current pads with zeros
+func (d Decimal) Round(scale int32, mode RoundingMode) Decimal {
+	if scale >= d.scale {
+		return Decimal{coef: d.rescale(scale), scale: scale}
+	}
+	return Decimal{coef: roundQuotient(d.int(), pow10(d.scale-scale), mode), scale: scale}
+}
+
+// RoundToStep returns the multiple of step (tick size or lot size) chosen by mode,
+// not positive step keeps d as is
+func (d Decimal) RoundToStep(step Decimal, mode RoundingMode) Decimal {
+	if step.Sign() <= 0 {
+		return d
+	}
+
+	a, b, scale := align(d, step)
+	q := roundQuotient(a, b, mode)
+	return Decimal{coef: q.Mul(q, b), scale: scale}
+}
+
+// Float64 returns the nearest float
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// String formats d with all digits of its scale, 0.8200 keeps trailing zeros
+func (d Decimal) String() string {
+	digits := new(big.Int).Abs(d.int()).String()
+
+	sign := ""
+	if d.Sign() < 0 {
+		sign = "-"
+	}
+
+	if d.scale <= 0 {
+		if d.IsZero() {
+			return "0"
+		}
+		return sign + digits + strings.Repeat("0", int(-d.scale))
+	}
+
+	if pad := int(d.scale) + 1 - len(digits); pad > 0 {
+		digits = strings.Repeat("0", pad) + digits
+	}
+	point := len(digits) - int(d.scale)
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+// StringFixed formats d rounded half even to scale digits after the point
+func (d Decimal) StringFixed(scale int32) string {
+	return d.Round(scale, RoundHalfEven).String()
+}
+
+// MarshalJSON writes the decimal as a string like exchange APIs do
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(d.String())), nil
+}
+
+// UnmarshalJSON reads both "0.8205" and 0.8205, empty string and null are zero
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+	if s == "" || s == "null" {
+		*d = Decimal{}
+		return nil
+	}
+
+	parsed, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
@@ -0,0 +1,148 @@
+package himath
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{input: "0.8205", want: "0.8205"},
+		{input: "57506.50", want: "57506.50"},
+		{input: "-12", want: "-12"},
+		{input: "-0.05", want: "-0.05"},
+		{input: ".5", want: "0.5"},
+		{input: "+3.10", want: "3.10"},
+		{input: "1e-5", want: "0.00001"},
+		{input: "2.5E3", want: "2500"},
+		{input: " 7 ", want: "7"},
+		{input: "", wantErr: true},
+		{input: "-", wantErr: true},
+		{input: "1.2.3", wantErr: true},
+		{input: "1-2", wantErr: true},
+		{input: "abc", wantErr: true},
+		{input: "1e", wantErr: true},
+		{input: "1e1000", want: "1" + strings.Repeat("0", 1000)},
+		{input: "1e1001", wantErr: true},
+		{input: "1e-2147483648", wantErr: true},
+		{input: "1e2147483647", wantErr: true},
+		{input: "1.5e-2147483647", wantErr: true},
+		{input: "1e9999999999", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseDecimal(tt.input)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidDecimal)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got.String())
+		})
+	}
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	a := MustParseDecimal("0.1")
+	b := MustParseDecimal("0.2")
+
+	assert.Equal(t, "0.3", a.Add(b).String())
+	assert.True(t, a.Add(b).Equal(MustParseDecimal("0.30")))
+	assert.Equal(t, "-0.1", a.Sub(b).String())
+	assert.Equal(t, "0.02", a.Mul(b).String())
+	assert.Equal(t, "0.1", a.Neg().Abs().String())
+	assert.Equal(t, "0", Decimal{}.String())
+	assert.True(t, Decimal{}.IsZero())
+
+	quo, err := MustParseDecimal("1").Div(MustParseDecimal("3"), 4, RoundHalfEven)
+	assert.NoError(t, err)
+	assert.Equal(t, "0.3333", quo.String())
+
+	quo, err = MustParseDecimal("-2").Div(MustParseDecimal("3"), 2, RoundDown)
+	assert.NoError(t, err)
+	assert.Equal(t, "-0.67", quo.String())
+
+	_, err = a.Div(Decimal{}, 2, RoundHalfEven)
+	assert.ErrorIs(t, err, ErrDivisionByZero)
+
+	assert.True(t, a.LessThan(b))
+	assert.True(t, b.GreaterThan(a))
+	assert.Equal(t, 0, MustParseDecimal("1.50").Cmp(MustParseDecimal("1.5")))
+
+	assert.Equal(t, "0.8205", DecimalFromFloat(0.8205).String())
+	assert.Equal(t, 0.8205, MustParseDecimal("0.8205").Float64())
+}
+
+func TestDecimalRound(t *testing.T) {
+	tests := []struct {
+		value string
+		scale int32
+		mode  RoundingMode
+		want  string
+	}{
+		// float math.Round(0.8205*1000) gives 820 because 0.8205 is stored as 0.82049999...
+		{value: "0.8205", scale: 3, mode: RoundHalfUp, want: "0.821"},
+		{value: "0.8205", scale: 3, mode: RoundHalfEven, want: "0.820"},
+		{value: "0.8215", scale: 3, mode: RoundHalfEven, want: "0.822"},
+		{value: "0.8205", scale: 3, mode: RoundDown, want: "0.820"},
+		{value: "0.8201", scale: 3, mode: RoundUp, want: "0.821"},
+		{value: "-0.8205", scale: 3, mode: RoundHalfUp, want: "-0.821"},
+		{value: "-0.8201", scale: 3, mode: RoundDown, want: "-0.821"},
+		{value: "-0.8209", scale: 3, mode: RoundUp, want: "-0.820"},
+		{value: "1.5", scale: 3, mode: RoundDown, want: "1.500"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			assert.Equal(t, tt.want, MustParseDecimal(tt.value).Round(tt.scale, tt.mode).String())
+		})
+	}
+}
+
+func TestDecimalRoundToStep(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		step  string
+		mode  RoundingMode
+		want  string
+	}{
+		{name: "tick down", value: "57506.57", step: "0.5", mode: RoundDown, want: "57506.50"},
+		{name: "tick up", value: "57506.57", step: "0.5", mode: RoundUp, want: "57507.00"},
+		{name: "tick half up", value: "0.82055", step: "0.0001", mode: RoundHalfUp, want: "0.82060"},
+		{name: "tick half even", value: "0.82055", step: "0.0001", mode: RoundHalfEven, want: "0.82060"},
+		{name: "tick half even tie to even", value: "0.82045", step: "0.0001", mode: RoundHalfEven, want: "0.82040"},
+		{name: "lot floor", value: "0.3", step: "0.1", mode: RoundDown, want: "0.3"},
+		{name: "lot step 5", value: "17", step: "5", mode: RoundDown, want: "15"},
+		{name: "zero step", value: "1.23", step: "0", mode: RoundDown, want: "1.23"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MustParseDecimal(tt.value).RoundToStep(MustParseDecimal(tt.step), tt.mode)
+			assert.Equal(t, tt.want, got.String())
+		})
+	}
+}
+
+func TestDecimalJSON(t *testing.T) {
+	var v struct {
+		Price Decimal `json:"price"`
+		Qty   Decimal `json:"qty"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(`{"price":"0.8205","qty":12.5}`), &v))
+	assert.Equal(t, "0.8205", v.Price.String())
+	assert.Equal(t, "12.5", v.Qty.String())
+
+	data, err := json.Marshal(v)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"price":"0.8205","qty":"12.5"}`, string(data))
+
+	assert.Error(t, json.Unmarshal([]byte(`{"price":"x"}`), &v))
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"price":"1e-2147483648"}`), &v), ErrInvalidDecimal)
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"qty":1e5000}`), &v), ErrInvalidDecimal)
+}
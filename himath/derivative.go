@@ -0,0 +1,129 @@
+package himath
+
+import "math"
+
+// DifferenceScheme points used by the finite difference
+type DifferenceScheme int
+
+const (
+	// Central uses points on both sides, it is exact for quadratics
+	Central DifferenceScheme = iota
+	// Forward uses the point and the following ones, e.g. (f[i+1] - f[i]) / h
+	Forward
+	// Backward uses the point and the previous ones, it does not look ahead and is safe for live data
+	Backward
+)
+
+// EdgeMode what to do with points where the scheme needs values beyond the series
+type EdgeMode int
+
+const (
+	// EdgeNaN keeps the length of the series and puts NaN at the edges
+	EdgeNaN EdgeMode = iota
+	// EdgeDrop returns only points where the scheme fits, like CentralDerivative
+	EdgeDrop
+	// EdgeOneSided keeps the length and uses Forward scheme at the start and Backward scheme at the end
+	EdgeOneSided
+)
+
+// DerivativeOptions configure Derivative, the zero value is the first order central difference with step 1
+type DerivativeOptions struct {
+	Scheme DifferenceScheme
+	// Order of the derivative: 1 for slope, 2 for curvature, 0 means 1
+	Order int
+	// Step distance between points, e.g. candle length in the unit of the slope, 0 means 1
+	Step float64
+	Edge EdgeMode
+}
+
+// stencil returns the number of points the scheme needs before and after the current one
+func stencil(scheme DifferenceScheme, order int) (before, after int) {
+	switch scheme {
+	case Forward:
+		return 0, order
+	case Backward:
+		return order, 0
+	}
+	return 1, 1
+}
+
+// edgeScheme returns the first scheme of Forward, Backward and Central that fits at the index
+func edgeScheme(i, n, order int) DifferenceScheme {
+	for _, scheme := range []DifferenceScheme{Forward, Backward} {
+		if before, after := stencil(scheme, order); i >= before && i+after < n {
+			return scheme
+		}
+	}
+	return Central
+}
+
+// difference returns finite difference of the scheme at the index, the stencil must fit into values
+func difference(values []float64, i int, scheme DifferenceScheme, order int, step float64) float64 {
+	if order == 1 {
+		switch scheme {
+		case Forward:
+			return (values[i+1] - values[i]) / step
+		case Backward:
+			return (values[i] - values[i-1]) / step
+		}
+		return (values[i+1] - values[i-1]) / (2 * step)
+	}
+
+	h2 := step * step
+	switch scheme {
+	case Forward:
+		return (values[i+2] - 2*values[i+1] + values[i]) / h2
+	case Backward:
+		return (values[i] - 2*values[i-1] + values[i-2]) / h2
+	}
+	return (values[i+1] - 2*values[i] + values[i-1]) / h2
+}
+
+// Derivative returns finite difference derivative of values sampled with a constant step
+//
+// First order: forward (f[i+1] - f[i]) / h, backward (f[i] - f[i-1]) / h, central (f[i+1] - f[i-1]) / 2h.
+// Second order: forward (f[i+2] - 2f[i+1] + f[i]) / h^2, backward (f[i] - 2f[i-1] + f[i-2]) / h^2,
+// central (f[i+1] - 2f[i] + f[i-1]) / h^2.
+func Derivative(values []float64, options DerivativeOptions) ([]float64, error) {
+	order, step := options.Order, options.Step
+	if order == 0 {
+		order = 1
+	}
+	if step == 0 {
+		step = 1
+	}
+	if order < 1 || order > 2 || !(step > 0) || math.IsInf(step, 1) {
+		return nil, ErrInvalidParameter
+	}
+
+	before, after := stencil(options.Scheme, order)
+	n := len(values)
+	minimum := before + after + 1
+	if options.Edge == EdgeOneSided {
+		minimum = order + 1
+	}
+	if n < minimum {
+		return nil, ErrInsufficientData
+	}
+
+	if options.Edge == EdgeDrop {
+		output := make([]float64, n-before-after)
+		for i := range output {
+			output[i] = difference(values, i+before, options.Scheme, order, step)
+		}
+		return output, nil
+	}
+
+	output := make([]float64, n)
+	for i := range output {
+		switch {
+		case i >= before && i < n-after:
+			output[i] = difference(values, i, options.Scheme, order, step)
+		case options.Edge == EdgeNaN:
+			output[i] = math.NaN()
+		default:
+			output[i] = difference(values, i, edgeScheme(i, n, order), order, step)
+		}
+	}
+	return output, nil
+}
@@ -0,0 +1,85 @@
+package himath
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDerivative(t *testing.T) {
+	// f(x) = x^2 sampled at x = 0, 0.5, 1, 1.5, 2
+	square := []float64{0, 0.25, 1, 2.25, 4}
+
+	tests := []struct {
+		name    string
+		values  []float64
+		options DerivativeOptions
+		want    []float64
+	}{
+		{
+			name:    "central keeps length with NaN edges",
+			values:  square,
+			options: DerivativeOptions{Step: 0.5},
+			want:    []float64{nan, 1, 2, 3, nan},
+		},
+		{
+			name:    "central drops edges",
+			values:  square,
+			options: DerivativeOptions{Step: 0.5, Edge: EdgeDrop},
+			want:    []float64{1, 2, 3},
+		},
+		{
+			name:    "central one-sided edges",
+			values:  square,
+			options: DerivativeOptions{Step: 0.5, Edge: EdgeOneSided},
+			want:    []float64{0.5, 1, 2, 3, 3.5},
+		},
+		{
+			name:    "forward",
+			values:  square,
+			options: DerivativeOptions{Scheme: Forward, Step: 0.5},
+			want:    []float64{0.5, 1.5, 2.5, 3.5, nan},
+		},
+		{
+			name:    "backward",
+			values:  square,
+			options: DerivativeOptions{Scheme: Backward, Step: 0.5, Edge: EdgeDrop},
+			want:    []float64{0.5, 1.5, 2.5, 3.5},
+		},
+		{
+			name:    "second order central",
+			values:  square,
+			options: DerivativeOptions{Order: 2, Step: 0.5, Edge: EdgeOneSided},
+			want:    []float64{2, 2, 2, 2, 2},
+		},
+		{
+			name:    "second order forward on three points",
+			values:  []float64{0, 1, 4},
+			options: DerivativeOptions{Scheme: Forward, Order: 2, Edge: EdgeOneSided},
+			want:    []float64{2, 2, 2},
+		},
+		{
+			name:    "second order backward",
+			values:  []float64{0, 1, 4, 9},
+			options: DerivativeOptions{Scheme: Backward, Order: 2},
+			want:    []float64{nan, nan, 2, 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Derivative(tt.values, tt.options)
+			assert.NoError(t, err)
+			assertSeries(t, tt.want, got)
+		})
+	}
+
+	_, err := Derivative(square, DerivativeOptions{Order: 3})
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+	_, err = Derivative(square, DerivativeOptions{Step: -1})
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+	_, err = Derivative([]float64{1, 2}, DerivativeOptions{})
+	assert.ErrorIs(t, err, ErrInsufficientData)
+
+	oneSided, err := Derivative([]float64{1, 2}, DerivativeOptions{Edge: EdgeOneSided})
+	assert.NoError(t, err)
+	assertSeries(t, []float64{1, 1}, oneSided)
+}
@@ -0,0 +1,70 @@
+// Package himath contains trading math: indicators, statistics, risk and position formulas.
+//
+// Invalid input is handled by one contract, no function panics:
+//   - functions over series return an error when the input shape is wrong:
+//     ErrLengthMismatch for series of different lengths, ErrInsufficientData for too short series,
+//     ErrInvalidPeriod and ErrInvalidParameter for out of range arguments;
+//   - values that are undefined for valid input are NaN: mean of an empty series, return from a zero
+//     or negative price, indicator values before warm-up. NaN inputs propagate to the results
+//     unless the function documents that it skips them;
+//   - scalar formulas such as CalcPnlPcnt follow IEEE 754 and give ±Inf or NaN on division by zero,
+//     their Decimal counterparts return ErrDivisionByZero instead.
+//
+// Exceptions kept for compatibility are documented on the function, e.g. PercentFormula returns 0
+// for zero initial value and Correlation returns 0 for undefined correlation.
+package himath
+
+import "errors"
+
+var (
+	// ErrInvalidPeriod is returned when an indicator period is not positive.
+	ErrInvalidPeriod = errors.New("period must be positive")
+	// ErrLengthMismatch is returned when input series have different lengths.
+	ErrLengthMismatch = errors.New("series have different length")
+	// ErrInsufficientData is returned when the series is too short for the calculation.
+	ErrInsufficientData = errors.New("insufficient data")
+	// ErrUnknownTimeframe is returned for timeframe codes not listed in consts.GetAllTimeframes.
+	ErrUnknownTimeframe = errors.New("unknown timeframe")
+	// ErrInvalidParameter is returned when a model parameter is out of its valid range.
+	ErrInvalidParameter = errors.New("parameter out of range")
+)
+
+var (
+	// ErrInvalidSide is returned when side is neither consts.Buy nor consts.Sell.
+	ErrInvalidSide = errors.New("side must be Buy or Sell")
+	// ErrInvalidQty is returned for not positive quantities and for closing more than the position holds.
+	ErrInvalidQty = errors.New("invalid qty")
+	// ErrInvalidPrice is returned for not positive prices.
+	ErrInvalidPrice = errors.New("invalid price")
+	// ErrInvalidLeverage is returned when leverage is less than 1.
+	ErrInvalidLeverage = errors.New("leverage must be at least 1")
+)
+
+var (
+	// ErrInvalidStopLoss is returned when the stop-loss is not on the losing side of the entry.
+	ErrInvalidStopLoss = errors.New("stop-loss must be below entry for Buy and above entry for Sell")
+	// ErrBelowMinimum is returned when the order is smaller than the instrument allows.
+	ErrBelowMinimum = errors.New("order is below instrument minimum")
+	// ErrInsufficientMargin is returned when the order margin exceeds the equity.
+	ErrInsufficientMargin = errors.New("insufficient margin")
+	// ErrLiquidationBeforeStop is returned when the position would be liquidated before its stop-loss.
+	ErrLiquidationBeforeStop = errors.New("liquidation price is reached before stop-loss")
+	// ErrNoEdge is returned when Kelly criterion gives no positive fraction to risk.
+	ErrNoEdge = errors.New("strategy has no positive edge")
+)
+
+var (
+	// ErrInvalidDecimal is returned when a string is not a decimal number.
+	ErrInvalidDecimal = errors.New("invalid decimal")
+	// ErrDivisionByZero is returned when a decimal is divided by zero.
+	ErrDivisionByZero = errors.New("division by zero")
+)
+
+var (
+	// ErrPriceNotOnTick is returned when the price is not a multiple of the tick size.
+	ErrPriceNotOnTick = errors.New("price is not a multiple of tick size")
+	// ErrQtyNotOnStep is returned when the qty is not a multiple of the qty step.
+	ErrQtyNotOnStep = errors.New("qty is not a multiple of qty step")
+	// ErrAboveMaximum is returned when the order is bigger than the instrument allows.
+	ErrAboveMaximum = errors.New("order is above instrument maximum")
+)
@@ -20,9 +20,46 @@ func CalcIM(value, leverage float64) float64 {
 	return value / leverage
 }
 
-// CalcPnlPcnt calculate pnl in percent
+// CalcPnlPcnt calculate pnl in percent of initial margin
 //
-// Formula: unrealisedPnl / (Value / 100)
+// Formula: ToPercent(unrealisedPnl / IM)
 func CalcPnlPcnt(unrealisedPnl, IM float64) float64 {
-	return unrealisedPnl / (IM / 100)
+	return ToPercent(unrealisedPnl / IM)
+}
+
+var hundred = NewDecimal(100, 0)
+
+// CalcStopLossPcntDecimal calculate pcnt of stopLoss in exact decimals
+//
+// Formula:  (stopLoss - avgPrice) * 100 * leverage / avgPrice
+func CalcStopLossPcntDecimal(stopLoss, avgPrice, leverage Decimal, side string) (Decimal, error) {
+	pcnt, err := stopLoss.Sub(avgPrice).Mul(hundred).Mul(leverage).Div(avgPrice, DivisionScale, RoundHalfEven)
+	if err != nil {
+		return Decimal{}, err
+	}
+	if side == consts.Sell {
+		return pcnt.Neg(), nil
+	}
+	return pcnt, nil
+}
+
+// CalcIMDecimal calculate initial margin in exact decimals
+//
+// Formula: value / leverage
+func CalcIMDecimal(value, leverage Decimal) (Decimal, error) {
+	return value.Div(leverage, DivisionScale, RoundHalfEven)
+}
+
+// CalcInitialMarginDecimal calculate initial margin of qty in exact decimals
+//
+// Formula: qty * entryPrice / leverage
+func CalcInitialMarginDecimal(qty, entryPrice, leverage Decimal) (Decimal, error) {
+	return CalcIMDecimal(qty.Mul(entryPrice), leverage)
+}
+
+// CalcPnlPcntDecimal calculate pnl in percent in exact decimals
+//
+// Formula: unrealisedPnl * 100 / IM
+func CalcPnlPcntDecimal(unrealisedPnl, IM Decimal) (Decimal, error) {
+	return unrealisedPnl.Mul(hundred).Div(IM, DivisionScale, RoundHalfEven)
 }
@@ -39,3 +39,24 @@ func TestCalcStopLossPcnt(t *testing.T) {
 		})
 	}
 }
+
+func TestDecimalFormules(t *testing.T) {
+	pcnt, err := CalcStopLossPcntDecimal(MustParseDecimal("0.8231"), MustParseDecimal("0.8205"), NewDecimal(10, 0), consts.Buy)
+	assert.NoError(t, err)
+	assert.Equal(t, "3.1687995124923827", pcnt.String())
+
+	pcnt, err = CalcStopLossPcntDecimal(MustParseDecimal("0.8231"), MustParseDecimal("0.8205"), NewDecimal(10, 0), consts.Sell)
+	assert.NoError(t, err)
+	assert.Equal(t, "-3.1687995124923827", pcnt.String())
+
+	im, err := CalcInitialMarginDecimal(MustParseDecimal("0.3"), MustParseDecimal("57506.5"), NewDecimal(20, 0))
+	assert.NoError(t, err)
+	assert.True(t, im.Equal(MustParseDecimal("862.5975")))
+
+	pnl, err := CalcPnlPcntDecimal(MustParseDecimal("-43.125"), im)
+	assert.NoError(t, err)
+	assert.Equal(t, "-4.9994348464956135", pnl.String())
+
+	_, err = CalcIMDecimal(MustParseDecimal("100"), Decimal{})
+	assert.ErrorIs(t, err, ErrDivisionByZero)
+}
@@ -25,14 +25,15 @@ import (
 //
 // Примечание:
 // Если входной срез содержит менее двух элементов, функция вернет пустой срез, так как нет достаточного количества данных для расчета изменений.
+// Если предыдущая цена равна нулю, изменение не определено и равно NaN.
 //
 // Ошибок не возникает.
 func PctChange(prices []float64) []float64 {
-	var pctChanges []float64
-	for i := 1; i < len(prices); i++ {
-		change := (prices[i] - prices[i-1]) / prices[i-1]
-		pctChanges = append(pctChanges, change)
+	if len(prices) < 2 {
+		return nil
 	}
+
+	pctChanges, _ := Series(prices).PctChangeTo(nil)
 	return pctChanges
 }
 
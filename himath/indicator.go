@@ -0,0 +1,429 @@
+package himath
+
+import "math"
+
+// Indicator is a stateful indicator updated with one value per closed candle.
+type Indicator interface {
+	// Update feeds the next value into the indicator.
+	Update(value float64)
+	// Value returns the current value of the indicator, NaN until Ready.
+	Value() float64
+	// Ready reports whether the indicator received enough values.
+	Ready() bool
+}
+
+// CandleIndicator is an Indicator calculated from high, low and close of a candle.
+//
+// Update(value) is equal to UpdateCandle(value, value, value).
+type CandleIndicator interface {
+	Indicator
+	UpdateCandle(high, low, closing float64)
+}
+
+// WarmUp feeds the history into the indicator from the oldest value to the newest.
+func WarmUp(indicator Indicator, history []float64) {
+	for _, v := range history {
+		indicator.Update(v)
+	}
+}
+
+// WarmUpCandles feeds the candles history into the indicator from the oldest candle to the newest.
+func WarmUpCandles(indicator CandleIndicator, high, low, closing []float64) error {
+	if len(high) != len(low) || len(high) != len(closing) {
+		return ErrLengthMismatch
+	}
+
+	for i := range closing {
+		indicator.UpdateCandle(high[i], low[i], closing[i])
+	}
+	return nil
+}
+
+// SmaIndicator simple moving average
+//
+// Formula: sum(values) / period
+type SmaIndicator struct {
+	window *window
+	sum    float64
+}
+
+func NewSmaIndicator(period int) (*SmaIndicator, error) {
+	if period <= 0 {
+		return nil, ErrInvalidPeriod
+	}
+	return &SmaIndicator{window: newWindow(period)}, nil
+}
+
+func (s *SmaIndicator) Update(value float64) {
+	old, evicted := s.window.push(value)
+	if evicted {
+		s.sum -= old
+	}
+	s.sum += value
+}
+
+func (s *SmaIndicator) Value() float64 {
+	if !s.Ready() {
+		return math.NaN()
+	}
+	return s.sum / float64(s.window.count)
+}
+
+func (s *SmaIndicator) Ready() bool {
+	return s.window.full()
+}
+
+// EmaIndicator exponential moving average seeded with the first value
+//
+// Formula: EmaFormula(price, EmaMultiplier(period), EMAp)
+type EmaIndicator struct {
+	period     int
+	multiplier float64
+	count      int
+	ema        float64
+}
+
+func NewEmaIndicator(period int) (*EmaIndicator, error) {
+	if period <= 0 {
+		return nil, ErrInvalidPeriod
+	}
+	return &EmaIndicator{period: period, multiplier: EmaMultiplier(float64(period))}, nil
+}
+
+func (e *EmaIndicator) Update(value float64) {
+	if e.count == 0 {
+		e.ema = value
+	} else {
+		e.ema = EmaFormula(value, e.multiplier, e.ema)
+	}
+	e.count++
+}
+
+func (e *EmaIndicator) Value() float64 {
+	if !e.Ready() {
+		return math.NaN()
+	}
+	return e.ema
+}
+
+func (e *EmaIndicator) Ready() bool {
+	return e.count >= e.period
+}
+
+// WmaIndicator linearly weighted moving average, the newest value has the biggest weight
+//
+// Formula: sum(value[i] * (i + 1)) / (period * (period + 1) / 2)
+type WmaIndicator struct {
+	window   *window
+	sum      float64
+	weighted float64
+}
+
+func NewWmaIndicator(period int) (*WmaIndicator, error) {
+	if period <= 0 {
+		return nil, ErrInvalidPeriod
+	}
+	return &WmaIndicator{window: newWindow(period)}, nil
+}
+
+func (w *WmaIndicator) Update(value float64) {
+	old, evicted := w.window.push(value)
+	if evicted {
+		// every kept value loses one weight, the evicted one loses its last
+		w.weighted -= w.sum
+		w.sum -= old
+	}
+	w.weighted += value * float64(w.window.count)
+	w.sum += value
+}
+
+func (w *WmaIndicator) Value() float64 {
+	if !w.Ready() {
+		return math.NaN()
+	}
+	n := float64(w.window.count)
+	return w.weighted / (n * (n + 1) / 2)
+}
+
+func (w *WmaIndicator) Ready() bool {
+	return w.window.full()
+}
+
+// RsiIndicator relative strength index with Wilder smoothing
+//
+// Formula: 100 - 100 / (1 + avgGain / avgLoss)
+type RsiIndicator struct {
+	period  int
+	count   int
+	prev    float64
+	avgGain float64
+	avgLoss float64
+}
+
+func NewRsiIndicator(period int) (*RsiIndicator, error) {
+	if period <= 0 {
+		return nil, ErrInvalidPeriod
+	}
+	return &RsiIndicator{period: period}, nil
+}
+
+func (r *RsiIndicator) Update(value float64) {
+	if r.count == 0 {
+		r.prev = value
+		r.count++
+		return
+	}
+
+	gain, loss := 0.0, 0.0
+	if diff := value - r.prev; diff > 0 {
+		gain = diff
+	} else {
+		loss = -diff
+	}
+	r.prev = value
+
+	n := r.count // number of changes including the current one
+	if n <= r.period {
+		// the first average is a simple mean of the first period changes
+		r.avgGain += (gain - r.avgGain) / float64(n)
+		r.avgLoss += (loss - r.avgLoss) / float64(n)
+	} else {
+		p := float64(r.period)
+		r.avgGain = (r.avgGain*(p-1) + gain) / p
+		r.avgLoss = (r.avgLoss*(p-1) + loss) / p
+	}
+	r.count++
+}
+
+func (r *RsiIndicator) Value() float64 {
+	if !r.Ready() {
+		return math.NaN()
+	}
+	if r.avgLoss == 0 {
+		if r.avgGain == 0 {
+			return 50
+		}
+		return 100
+	}
+	return 100 - 100/(1+r.avgGain/r.avgLoss)
+}
+
+func (r *RsiIndicator) Ready() bool {
+	return r.count > r.period
+}
+
+// MacdIndicator moving average convergence divergence
+//
+// Formula: MACD = EMA(fast) - EMA(slow), Signal = EMA(signal) of MACD
+type MacdIndicator struct {
+	fast   *EmaIndicator
+	slow   *EmaIndicator
+	signal *EmaIndicator
+}
+
+func NewMacdIndicator(fast, slow, signal int) (*MacdIndicator, error) {
+	if fast <= 0 || slow <= 0 || signal <= 0 {
+		return nil, ErrInvalidPeriod
+	}
+
+	fastEma, _ := NewEmaIndicator(fast)
+	slowEma, _ := NewEmaIndicator(slow)
+	signalEma, _ := NewEmaIndicator(signal)
+
+	return &MacdIndicator{fast: fastEma, slow: slowEma, signal: signalEma}, nil
+}
+
+func (m *MacdIndicator) Update(value float64) {
+	m.fast.Update(value)
+	m.slow.Update(value)
+	m.signal.Update(m.fast.ema - m.slow.ema)
+}
+
+// Value returns MACD line
+func (m *MacdIndicator) Value() float64 {
+	if !m.Ready() {
+		return math.NaN()
+	}
+	return m.fast.ema - m.slow.ema
+}
+
+// Signal returns signal line
+func (m *MacdIndicator) Signal() float64 {
+	if !m.Ready() {
+		return math.NaN()
+	}
+	return m.signal.ema
+}
+
+// Histogram returns MACD - Signal
+func (m *MacdIndicator) Histogram() float64 {
+	return m.Value() - m.Signal()
+}
+
+func (m *MacdIndicator) Ready() bool {
+	return m.fast.Ready() && m.slow.Ready() && m.signal.Ready()
+}
+
+// BollingerBandsIndicator SMA with bands at multiplier population standard deviations
+//
+// Formula: Middle = SMA(period), Upper/Lower = Middle ± multiplier * std
+type BollingerBandsIndicator struct {
+	sma        *SmaIndicator
+	sumSquares float64
+	multiplier float64
+}
+
+func NewBollingerBandsIndicator(period int, multiplier float64) (*BollingerBandsIndicator, error) {
+	sma, err := NewSmaIndicator(period)
+	if err != nil {
+		return nil, err
+	}
+	return &BollingerBandsIndicator{sma: sma, multiplier: multiplier}, nil
+}
+
+func (b *BollingerBandsIndicator) Update(value float64) {
+	if b.sma.window.full() {
+		old := b.sma.window.values[b.sma.window.pos]
+		b.sumSquares -= old * old
+	}
+	b.sumSquares += value * value
+	b.sma.Update(value)
+}
+
+// Value returns middle band
+func (b *BollingerBandsIndicator) Value() float64 {
+	return b.sma.Value()
+}
+
+func (b *BollingerBandsIndicator) Upper() float64 {
+	return b.Value() + b.multiplier*b.StdDev()
+}
+
+func (b *BollingerBandsIndicator) Lower() float64 {
+	return b.Value() - b.multiplier*b.StdDev()
+}
+
+// StdDev returns population standard deviation of the window
+func (b *BollingerBandsIndicator) StdDev() float64 {
+	if !b.Ready() {
+		return math.NaN()
+	}
+	mean := b.sma.Value()
+	variance := b.sumSquares/float64(b.sma.window.count) - mean*mean
+	if variance < 0 {
+		variance = 0 // float rounding on flat windows
+	}
+	return math.Sqrt(variance)
+}
+
+func (b *BollingerBandsIndicator) Ready() bool {
+	return b.sma.Ready()
+}
+
+// AtrIndicator average true range with Wilder smoothing
+//
+// Formula: TR = max(high - low, |high - prevClose|, |low - prevClose|), ATR = RMA(period) of TR
+type AtrIndicator struct {
+	period    int
+	count     int
+	prevClose float64
+	atr       float64
+}
+
+func NewAtrIndicator(period int) (*AtrIndicator, error) {
+	if period <= 0 {
+		return nil, ErrInvalidPeriod
+	}
+	return &AtrIndicator{period: period}, nil
+}
+
+func (a *AtrIndicator) Update(value float64) {
+	a.UpdateCandle(value, value, value)
+}
+
+func (a *AtrIndicator) UpdateCandle(high, low, closing float64) {
+	tr := high - low
+	if a.count > 0 {
+		tr = math.Max(tr, math.Max(math.Abs(high-a.prevClose), math.Abs(low-a.prevClose)))
+	}
+	a.prevClose = closing
+	a.count++
+
+	if a.count <= a.period {
+		a.atr += (tr - a.atr) / float64(a.count)
+	} else {
+		p := float64(a.period)
+		a.atr = (a.atr*(p-1) + tr) / p
+	}
+}
+
+func (a *AtrIndicator) Value() float64 {
+	if !a.Ready() {
+		return math.NaN()
+	}
+	return a.atr
+}
+
+func (a *AtrIndicator) Ready() bool {
+	return a.count >= a.period
+}
+
+// StochasticIndicator stochastic oscillator
+//
+// Formula: K = (close - lowestLow) / (highestHigh - lowestLow) * 100, D = SMA(dPeriod) of K
+type StochasticIndicator struct {
+	highs *window
+	lows  *window
+	k     float64
+	d     *SmaIndicator
+}
+
+func NewStochasticIndicator(kPeriod, dPeriod int) (*StochasticIndicator, error) {
+	if kPeriod <= 0 {
+		return nil, ErrInvalidPeriod
+	}
+	d, err := NewSmaIndicator(dPeriod)
+	if err != nil {
+		return nil, err
+	}
+	return &StochasticIndicator{highs: newWindow(kPeriod), lows: newWindow(kPeriod), d: d}, nil
+}
+
+func (s *StochasticIndicator) Update(value float64) {
+	s.UpdateCandle(value, value, value)
+}
+
+func (s *StochasticIndicator) UpdateCandle(high, low, closing float64) {
+	s.highs.push(high)
+	s.lows.push(low)
+
+	lowestLow, _ := s.lows.minMax()
+	_, highestHigh := s.highs.minMax()
+
+	if highestHigh == lowestLow {
+		s.k = 50 // flat range, close is in the middle of it
+	} else {
+		s.k = (closing - lowestLow) / (highestHigh - lowestLow) * 100
+	}
+
+	if s.highs.full() {
+		s.d.Update(s.k)
+	}
+}
+
+// Value returns %K
+func (s *StochasticIndicator) Value() float64 {
+	if !s.Ready() {
+		return math.NaN()
+	}
+	return s.k
+}
+
+// D returns %D
+func (s *StochasticIndicator) D() float64 {
+	return s.d.Value()
+}
+
+func (s *StochasticIndicator) Ready() bool {
+	return s.d.Ready()
+}
@@ -0,0 +1,191 @@
+package himath
+
+import (
+	"github.com/cinar/indicator"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+// testCandles generates deterministic candles around 100 with wicks on both sides of the close.
+func testCandles(n int) (high, low, closing []float64) {
+	high = make([]float64, n)
+	low = make([]float64, n)
+	closing = make([]float64, n)
+	for i := 0; i < n; i++ {
+		x := float64(i)
+		closing[i] = 100 + 10*math.Sin(x/7) + 3*math.Cos(x/2.3) + x/20
+		high[i] = closing[i] + 1 + math.Abs(math.Sin(x))
+		low[i] = closing[i] - 1 - math.Abs(math.Cos(x/3))
+	}
+	return high, low, closing
+}
+
+// assertStream updates the indicator per value and compares with want since the first ready value.
+func assertStream(t *testing.T, ind Indicator, values, want []float64, delta float64) {
+	t.Helper()
+	for i, v := range values {
+		ind.Update(v)
+		if !ind.Ready() {
+			assert.True(t, math.IsNaN(ind.Value()), "index %d: value before ready", i)
+			continue
+		}
+		assert.InDelta(t, want[i], ind.Value(), delta, "index %d", i)
+	}
+	assert.True(t, ind.Ready())
+}
+
+func TestSmaIndicator(t *testing.T) {
+	_, _, closing := testCandles(200)
+
+	for _, period := range []int{1, 5, 20} {
+		sma, err := NewSmaIndicator(period)
+		assert.NoError(t, err)
+		assertStream(t, sma, closing, indicator.Sma(period, closing), 1e-9)
+	}
+
+	_, err := NewSmaIndicator(0)
+	assert.ErrorIs(t, err, ErrInvalidPeriod)
+}
+
+func TestEmaIndicator(t *testing.T) {
+	_, _, closing := testCandles(200)
+
+	ema, err := NewEmaIndicator(12)
+	assert.NoError(t, err)
+	assertStream(t, ema, closing, indicator.Ema(12, closing), 1e-9)
+}
+
+func TestWmaIndicator(t *testing.T) {
+	wma, err := NewWmaIndicator(3)
+	assert.NoError(t, err)
+
+	values := []float64{1, 2, 3, 4, 10}
+	want := []float64{0, 0, 14.0 / 6, 20.0 / 6, 41.0 / 6}
+	assertStream(t, wma, values, want, 1e-9)
+}
+
+func TestRsiIndicator(t *testing.T) {
+	_, _, closing := testCandles(300)
+	period := 14
+
+	// cinar counts the first bar as a zero change, so reference RMA is built over the changes only
+	gains := make([]float64, len(closing)-1)
+	losses := make([]float64, len(closing)-1)
+	for i := 1; i < len(closing); i++ {
+		gains[i-1] = math.Max(closing[i]-closing[i-1], 0)
+		losses[i-1] = math.Max(closing[i-1]-closing[i], 0)
+	}
+	avgGains := indicator.Rma(period, gains)
+	avgLosses := indicator.Rma(period, losses)
+
+	want := make([]float64, len(closing))
+	for i := 1; i < len(closing); i++ {
+		want[i] = 100 - 100/(1+avgGains[i-1]/avgLosses[i-1])
+	}
+
+	rsi, err := NewRsiIndicator(period)
+	assert.NoError(t, err)
+	assertStream(t, rsi, closing, want, 1e-9)
+
+	// after warm up the difference with cinar seeding fades out
+	_, cinarRsi := indicator.RsiPeriod(period, closing)
+	assert.InDelta(t, cinarRsi[len(cinarRsi)-1], rsi.Value(), 1e-3)
+}
+
+func TestMacdIndicator(t *testing.T) {
+	_, _, closing := testCandles(200)
+
+	macd, err := NewMacdIndicator(12, 26, 9)
+	assert.NoError(t, err)
+
+	wantMacd, wantSignal := indicator.Macd(closing)
+	for i, v := range closing {
+		macd.Update(v)
+		if !macd.Ready() {
+			continue
+		}
+		assert.InDelta(t, wantMacd[i], macd.Value(), 1e-9)
+		assert.InDelta(t, wantSignal[i], macd.Signal(), 1e-9)
+		assert.InDelta(t, wantMacd[i]-wantSignal[i], macd.Histogram(), 1e-9)
+	}
+	assert.True(t, macd.Ready())
+}
+
+func TestBollingerBandsIndicator(t *testing.T) {
+	_, _, closing := testCandles(200)
+
+	bb, err := NewBollingerBandsIndicator(20, 2)
+	assert.NoError(t, err)
+
+	middle, upper, lower := indicator.BollingerBands(closing)
+	for i, v := range closing {
+		bb.Update(v)
+		if !bb.Ready() {
+			continue
+		}
+		assert.InDelta(t, middle[i], bb.Value(), 1e-6)
+		assert.InDelta(t, upper[i], bb.Upper(), 1e-6)
+		assert.InDelta(t, lower[i], bb.Lower(), 1e-6)
+	}
+}
+
+func TestAtrIndicator(t *testing.T) {
+	high, low, closing := testCandles(200)
+	period := 14
+
+	tr := make([]float64, len(closing))
+	tr[0] = high[0] - low[0]
+	for i := 1; i < len(closing); i++ {
+		tr[i] = math.Max(high[i]-low[i], math.Max(math.Abs(high[i]-closing[i-1]), math.Abs(low[i]-closing[i-1])))
+	}
+	want := indicator.Rma(period, tr)
+
+	atr, err := NewAtrIndicator(period)
+	assert.NoError(t, err)
+	for i := range closing {
+		atr.UpdateCandle(high[i], low[i], closing[i])
+		if atr.Ready() {
+			assert.InDelta(t, want[i], atr.Value(), 1e-9)
+		}
+	}
+}
+
+func TestStochasticIndicator(t *testing.T) {
+	high, low, closing := testCandles(200)
+
+	stoch, err := NewStochasticIndicator(14, 3)
+	assert.NoError(t, err)
+
+	k, d := indicator.StochasticOscillator(high, low, closing)
+	for i := range closing {
+		stoch.UpdateCandle(high[i], low[i], closing[i])
+		if !stoch.Ready() {
+			continue
+		}
+		assert.InDelta(t, k[i], stoch.Value(), 1e-9)
+		assert.InDelta(t, d[i], stoch.D(), 1e-9)
+	}
+	assert.True(t, stoch.Ready())
+}
+
+func TestWarmUp(t *testing.T) {
+	high, low, closing := testCandles(100)
+
+	history, live := closing[:80], closing[80:]
+
+	warm, _ := NewEmaIndicator(10)
+	WarmUp(warm, history)
+	for _, v := range live {
+		warm.Update(v)
+	}
+
+	full, _ := NewEmaIndicator(10)
+	WarmUp(full, closing)
+	assert.Equal(t, full.Value(), warm.Value())
+
+	atr, _ := NewAtrIndicator(14)
+	assert.ErrorIs(t, WarmUpCandles(atr, high, low[1:], closing), ErrLengthMismatch)
+	assert.NoError(t, WarmUpCandles(atr, high, low, closing))
+	assert.True(t, atr.Ready())
+}
@@ -0,0 +1,164 @@
+package himath
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// Instrument trading constraints of a contract, see ParseBybitInstruments
+type Instrument struct {
+	Symbol      string
+	TickSize    float64 // price step
+	MinPrice    float64
+	MaxPrice    float64 // 0 means no limit
+	QtyStep     float64 // lot size
+	MinQty      float64
+	MaxQty      float64 // 0 means no limit
+	MinNotional float64 // min qty * price of an order
+	PriceScale  int32   // digits after the point of prices sent to the API
+}
+
+// onStep rounds value to a multiple of step in exact decimals, so 0.3 stays 0.3 with step 0.1.
+// NaN and ±Inf values give NaN.
+func onStep(value, step float64, mode RoundingMode) float64 {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return math.NaN()
+	}
+	return DecimalFromFloat(value).RoundToStep(DecimalFromFloat(step), mode).Float64()
+}
+
+// RoundPriceDown rounds price down to the tick size
+func (i Instrument) RoundPriceDown(price float64) float64 {
+	return onStep(price, i.TickSize, RoundDown)
+}
+
+// RoundPriceUp rounds price up to the tick size
+func (i Instrument) RoundPriceUp(price float64) float64 {
+	return onStep(price, i.TickSize, RoundUp)
+}
+
+// RoundPrice rounds price to the nearest tick, ties away from zero
+func (i Instrument) RoundPrice(price float64) float64 {
+	return onStep(price, i.TickSize, RoundHalfUp)
+}
+
+// FloorQty rounds qty down to the qty step
+func (i Instrument) FloorQty(qty float64) float64 {
+	return onStep(qty, i.QtyStep, RoundDown)
+}
+
+// isOnStep reports whether value is an exact multiple of step, NaN and ±Inf never are
+func isOnStep(value, step float64) bool {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return false
+	}
+	if step <= 0 {
+		return true
+	}
+	v := DecimalFromFloat(value)
+	return v.Equal(v.RoundToStep(DecimalFromFloat(step), RoundDown))
+}
+
+// ValidateOrder checks that price and qty can be sent to the exchange as is, NaN and ±Inf are invalid
+func (i Instrument) ValidateOrder(price, qty float64) error {
+	switch {
+	case !(price > 0) || math.IsInf(price, 0):
+		return fmt.Errorf("%w: price %v", ErrInvalidPrice, price)
+	case !(qty > 0) || math.IsInf(qty, 0):
+		return fmt.Errorf("%w: qty %v", ErrInvalidQty, qty)
+	case !isOnStep(price, i.TickSize):
+		return fmt.Errorf("%w: price %v, tick size %v", ErrPriceNotOnTick, price, i.TickSize)
+	case !isOnStep(qty, i.QtyStep):
+		return fmt.Errorf("%w: qty %v, qty step %v", ErrQtyNotOnStep, qty, i.QtyStep)
+	case price < i.MinPrice:
+		return fmt.Errorf("%w: price %v, min price %v", ErrBelowMinimum, price, i.MinPrice)
+	case i.MaxPrice > 0 && price > i.MaxPrice:
+		return fmt.Errorf("%w: price %v, max price %v", ErrAboveMaximum, price, i.MaxPrice)
+	case qty < i.MinQty:
+		return fmt.Errorf("%w: qty %v, min qty %v", ErrBelowMinimum, qty, i.MinQty)
+	case i.MaxQty > 0 && qty > i.MaxQty:
+		return fmt.Errorf("%w: qty %v, max qty %v", ErrAboveMaximum, qty, i.MaxQty)
+	case price*qty < i.MinNotional:
+		return fmt.Errorf("%w: notional %v, min notional %v", ErrBelowMinimum, price*qty, i.MinNotional)
+	}
+	return nil
+}
+
+// FormatPrice formats price with PriceScale digits, or with digits of the tick size when PriceScale is 0
+func (i Instrument) FormatPrice(price float64) string {
+	scale := i.PriceScale
+	if scale == 0 {
+		scale = max(DecimalFromFloat(i.TickSize).Scale(), 0)
+	}
+	return DecimalFromFloat(price).Round(scale, RoundHalfUp).String()
+}
+
+// FormatQty formats qty with digits of the qty step
+func (i Instrument) FormatQty(qty float64) string {
+	scale := max(DecimalFromFloat(i.QtyStep).Scale(), 0)
+	return DecimalFromFloat(qty).Round(scale, RoundDown).String()
+}
+
+// bybitInstrumentsInfo response of GET /v5/market/instruments-info, linear, inverse and spot
+type bybitInstrumentsInfo struct {
+	RetCode int    `json:"retCode"`
+	RetMsg  string `json:"retMsg"`
+	Result  struct {
+		List []struct {
+			Symbol      string  `json:"symbol"`
+			PriceScale  Decimal `json:"priceScale"`
+			PriceFilter struct {
+				MinPrice Decimal `json:"minPrice"`
+				MaxPrice Decimal `json:"maxPrice"`
+				TickSize Decimal `json:"tickSize"`
+			} `json:"priceFilter"`
+			LotSizeFilter struct {
+				MinOrderQty      Decimal `json:"minOrderQty"`
+				MaxOrderQty      Decimal `json:"maxOrderQty"`
+				QtyStep          Decimal `json:"qtyStep"`
+				MinNotionalValue Decimal `json:"minNotionalValue"`
+				BasePrecision    Decimal `json:"basePrecision"` // spot qty step
+				MinOrderAmt      Decimal `json:"minOrderAmt"`   // spot min notional
+			} `json:"lotSizeFilter"`
+		} `json:"list"`
+	} `json:"result"`
+}
+
+// ParseBybitInstruments builds instruments from Bybit instruments-info JSON response
+func ParseBybitInstruments(data []byte) ([]Instrument, error) {
+	var response bybitInstrumentsInfo
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, err
+	}
+	if response.RetCode != 0 {
+		return nil, fmt.Errorf("bybit instruments-info: %d %s", response.RetCode, response.RetMsg)
+	}
+
+	instruments := make([]Instrument, 0, len(response.Result.List))
+	for _, item := range response.Result.List {
+		lot := item.LotSizeFilter
+
+		qtyStep := lot.QtyStep
+		if qtyStep.IsZero() {
+			qtyStep = lot.BasePrecision
+		}
+		minNotional := lot.MinNotionalValue
+		if minNotional.IsZero() {
+			minNotional = lot.MinOrderAmt
+		}
+
+		instruments = append(instruments, Instrument{
+			Symbol:      item.Symbol,
+			TickSize:    item.PriceFilter.TickSize.Float64(),
+			MinPrice:    item.PriceFilter.MinPrice.Float64(),
+			MaxPrice:    item.PriceFilter.MaxPrice.Float64(),
+			QtyStep:     qtyStep.Float64(),
+			MinQty:      lot.MinOrderQty.Float64(),
+			MaxQty:      lot.MaxOrderQty.Float64(),
+			MinNotional: minNotional.Float64(),
+			PriceScale:  int32(item.PriceScale.Float64()),
+		})
+	}
+	return instruments, nil
+}
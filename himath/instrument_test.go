@@ -0,0 +1,175 @@
+package himath
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+// bybitLinearInstruments trimmed GET /v5/market/instruments-info?category=linear response
+const bybitLinearInstruments = `{
+    "retCode": 0,
+    "retMsg": "OK",
+    "result": {
+        "category": "linear",
+        "list": [
+            {
+                "symbol": "BTCUSDT",
+                "contractType": "LinearPerpetual",
+                "status": "Trading",
+                "baseCoin": "BTC",
+                "quoteCoin": "USDT",
+                "deliveryFeeRate": "",
+                "priceScale": "2",
+                "leverageFilter": {"minLeverage": "1", "maxLeverage": "100.00", "leverageStep": "0.01"},
+                "priceFilter": {"minPrice": "0.10", "maxPrice": "1999999.80", "tickSize": "0.10"},
+                "lotSizeFilter": {
+                    "maxOrderQty": "1190.000",
+                    "minOrderQty": "0.001",
+                    "qtyStep": "0.001",
+                    "postOnlyMaxOrderQty": "1190.000",
+                    "maxMktOrderQty": "119.000",
+                    "minNotionalValue": "5"
+                }
+            },
+            {
+                "symbol": "XRPUSDT",
+                "contractType": "LinearPerpetual",
+                "status": "Trading",
+                "priceScale": "4",
+                "priceFilter": {"minPrice": "0.0001", "maxPrice": "199.9998", "tickSize": "0.0001"},
+                "lotSizeFilter": {"maxOrderQty": "6148400", "minOrderQty": "1", "qtyStep": "1", "minNotionalValue": "5"}
+            }
+        ],
+        "nextPageCursor": ""
+    },
+    "retExtInfo": {},
+    "time": 1707186451514
+}`
+
+// bybitSpotInstruments trimmed GET /v5/market/instruments-info?category=spot response
+const bybitSpotInstruments = `{
+    "retCode": 0,
+    "retMsg": "OK",
+    "result": {
+        "category": "spot",
+        "list": [
+            {
+                "symbol": "BTCUSDT",
+                "lotSizeFilter": {
+                    "basePrecision": "0.000001",
+                    "quotePrecision": "0.00000001",
+                    "minOrderQty": "0.000048",
+                    "maxOrderQty": "71.73956243",
+                    "minOrderAmt": "1",
+                    "maxOrderAmt": "2000000"
+                },
+                "priceFilter": {"tickSize": "0.01"}
+            }
+        ]
+    },
+    "retExtInfo": {},
+    "time": 1672712468011
+}`
+
+func TestParseBybitInstruments(t *testing.T) {
+	linear, err := ParseBybitInstruments([]byte(bybitLinearInstruments))
+	assert.NoError(t, err)
+	assert.Equal(t, []Instrument{
+		{
+			Symbol: "BTCUSDT", TickSize: 0.1, MinPrice: 0.1, MaxPrice: 1999999.8,
+			QtyStep: 0.001, MinQty: 0.001, MaxQty: 1190, MinNotional: 5, PriceScale: 2,
+		},
+		{
+			Symbol: "XRPUSDT", TickSize: 0.0001, MinPrice: 0.0001, MaxPrice: 199.9998,
+			QtyStep: 1, MinQty: 1, MaxQty: 6148400, MinNotional: 5, PriceScale: 4,
+		},
+	}, linear)
+
+	spot, err := ParseBybitInstruments([]byte(bybitSpotInstruments))
+	assert.NoError(t, err)
+	assert.Equal(t, []Instrument{
+		{Symbol: "BTCUSDT", TickSize: 0.01, QtyStep: 0.000001, MinQty: 0.000048, MaxQty: 71.73956243, MinNotional: 1},
+	}, spot)
+
+	_, err = ParseBybitInstruments([]byte(`{"retCode": 10001, "retMsg": "params error"}`))
+	assert.EqualError(t, err, "bybit instruments-info: 10001 params error")
+}
+
+func TestInstrumentRounding(t *testing.T) {
+	xrp := Instrument{TickSize: 0.0001, QtyStep: 1, PriceScale: 4}
+	btc := Instrument{TickSize: 0.1, QtyStep: 0.001}
+
+	tests := []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{name: "price down", got: xrp.RoundPriceDown(0.82057), want: 0.8205},
+		{name: "price up", got: xrp.RoundPriceUp(0.82051), want: 0.8206},
+		{name: "price on tick up", got: xrp.RoundPriceUp(0.8205), want: 0.8205},
+		{name: "price nearest", got: btc.RoundPrice(57506.25), want: 57506.3},
+		{name: "price tick 0.1", got: btc.RoundPriceDown(0.3), want: 0.3},
+		{name: "qty floor", got: btc.FloorQty(0.0129), want: 0.012},
+		{name: "qty floor step 1", got: xrp.FloorQty(151.9), want: 151},
+		{name: "no tick", got: Instrument{}.RoundPriceDown(1.23456), want: 1.23456},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.got)
+		})
+	}
+
+	for _, v := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		assert.True(t, math.IsNaN(xrp.RoundPriceDown(v)), v)
+		assert.True(t, math.IsNaN(xrp.RoundPriceUp(v)), v)
+		assert.True(t, math.IsNaN(btc.RoundPrice(v)), v)
+		assert.True(t, math.IsNaN(btc.FloorQty(v)), v)
+	}
+
+	assert.Equal(t, "0.8205", xrp.FormatPrice(0.82050000001))
+	assert.Equal(t, "57506.3", btc.FormatPrice(57506.25))
+	assert.Equal(t, "0.012", btc.FormatQty(0.0129))
+	assert.Equal(t, "151", xrp.FormatQty(151.9))
+}
+
+func TestInstrumentValidateOrder(t *testing.T) {
+	btc := Instrument{TickSize: 0.1, MinPrice: 0.1, MaxPrice: 1999999.8, QtyStep: 0.001, MinQty: 0.001, MaxQty: 1190, MinNotional: 5}
+
+	tests := []struct {
+		name    string
+		price   float64
+		qty     float64
+		wantErr error
+	}{
+		{name: "valid", price: 57506.3, qty: 0.003},
+		{name: "price off tick", price: 57506.35, qty: 0.003, wantErr: ErrPriceNotOnTick},
+		{name: "qty off step", price: 57506.3, qty: 0.0035, wantErr: ErrQtyNotOnStep},
+		{name: "qty above max", price: 57506.3, qty: 1191, wantErr: ErrAboveMaximum},
+		{name: "price above max", price: 2000000, qty: 0.001, wantErr: ErrAboveMaximum},
+		{name: "notional below min", price: 4000, qty: 0.001, wantErr: ErrBelowMinimum},
+		{name: "zero qty", price: 57506.3, qty: 0, wantErr: ErrInvalidQty},
+		{name: "zero price", price: 0, qty: 0.003, wantErr: ErrInvalidPrice},
+		{name: "negative price", price: -57506.3, qty: 0.003, wantErr: ErrInvalidPrice},
+		{name: "NaN price", price: math.NaN(), qty: 0.003, wantErr: ErrInvalidPrice},
+		{name: "Inf price", price: math.Inf(1), qty: 0.003, wantErr: ErrInvalidPrice},
+		{name: "NaN qty", price: 57506.3, qty: math.NaN(), wantErr: ErrInvalidQty},
+		{name: "Inf qty", price: 57506.3, qty: math.Inf(1), wantErr: ErrInvalidQty},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := btc.ValidateOrder(tt.price, tt.qty)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+
+	unlimited := Instrument{}
+	assert.ErrorIs(t, unlimited.ValidateOrder(math.NaN(), 1), ErrInvalidPrice)
+	assert.ErrorIs(t, unlimited.ValidateOrder(math.Inf(1), 1), ErrInvalidPrice)
+	assert.ErrorIs(t, unlimited.ValidateOrder(100, math.NaN()), ErrInvalidQty)
+	assert.ErrorIs(t, unlimited.ValidateOrder(100, math.Inf(1)), ErrInvalidQty)
+}
@@ -5,9 +5,11 @@ import (
 	"math"
 )
 
-func Correlation(data1, data2 []float64) []float64 {
+// Correlation returns expanding Pearson correlation of two series: value i is the correlation of the first i+1 points.
+// Undefined correlation, e.g. of the first point or of a flat prefix, is 0.
+func Correlation(data1, data2 []float64) ([]float64, error) {
 	if len(data1) != len(data2) {
-		panic("DIFFERENT LEN")
+		return nil, ErrLengthMismatch
 	}
 	correlation := make([]float64, 0, len(data1))
 	for i := 1; i <= len(data1); i++ {
@@ -19,7 +21,7 @@ func Correlation(data1, data2 []float64) []float64 {
 		}
 
 	}
-	return correlation
+	return correlation, nil
 }
 
 func IsPriceBigger3MA(price, ema, emb, ma float64) bool {
@@ -34,6 +36,8 @@ func ZScore(data []float64) []float64 {
 }
 
 // FindSpikes FindSpike рассчитывает z-оценку для элемента в массиве по указанному индексу
+//
+// Неопределённая z-оценка (нулевое или NaN stdDev) равна 0.
 func FindSpikes(data []float64, mean, stdDev float64) []float64 {
 	output := make([]float64, 0, len(data))
 	for _, v := range data {
@@ -50,12 +54,11 @@ func FindSpikes(data []float64, mean, stdDev float64) []float64 {
 // CentralDerivative
 //
 // (f'(x) = SMA(i - h) - SMA(i + h)) / 2h
-func CentralDerivative(sma []float64) []float64 {
-	derivative := make([]float64, len(sma)-2) // Уменьшаем на 2, так как не можем вычислить края
-	for i := 1; i < len(sma)-1; i++ {
-		derivative[i-1] = (sma[i+1] - sma[i-1]) / 2
-	}
-	return derivative
+//
+// Returns ErrInsufficientData for less than three points, NaN values of sma propagate to their neighbours.
+// It is Derivative with the default options and EdgeDrop.
+func CentralDerivative(sma []float64) ([]float64, error) {
+	return Derivative(sma, DerivativeOptions{Scheme: Central, Edge: EdgeDrop})
 }
 
 // varianceGeneral вычисляет дисперсию последовательности чисел по обобщённой формуле.
@@ -75,30 +78,32 @@ func varianceGeneral(floatSlice []float64) float64 {
 }
 
 // LogReturns calculates the logarithmic returns of a slice of prices.
-func LogReturns(prices []float64) []float64 {
+//
+// Returns ErrInsufficientData for less than two prices. The return is NaN when either price is not positive.
+func LogReturns(prices []float64) ([]float64, error) {
+	if len(prices) < 2 {
+		return nil, ErrInsufficientData
+	}
+
 	returns := make([]float64, len(prices)-1)
 	for i := 1; i < len(prices); i++ {
-		returns[i-1] = math.Log(prices[i] / prices[i-1])
+		returns[i-1] = LogReturn(prices[i-1], prices[i])
 	}
-	return returns
+	return returns, nil
 }
 
 // PercentFormula вычисляет процентное изменение между начальным и конечным значением.
+// Знак результата совпадает с направлением изменения и для отрицательного initialValue.
 //
-// Формула: (finalValue - initialValue) / (initialValue / 100)
+// Формула: ToPercent(SimpleReturn(initialValue, finalValue))
 func PercentFormula(finalValue, initialValue float64) float64 {
 	if initialValue == 0 {
 		return 0 // Возвращает 0, чтобы избежать деления на ноль
 	}
 
-	pcnt := (finalValue - initialValue) / (initialValue / 100)
-
-	if finalValue > initialValue {
-		pcnt = math.Abs(pcnt)
-	}
-
-	if finalValue < initialValue && pcnt > 0 {
-		pcnt = pcnt * -1
+	pcnt := ToPercent(SimpleReturn(initialValue, finalValue))
+	if initialValue < 0 {
+		pcnt = -pcnt // Деление на отрицательное значение меняет знак, возвращаем знак направления изменения
 	}
 
 	return pcnt
@@ -108,11 +113,13 @@ func PercentFormula(finalValue, initialValue float64) float64 {
 // корректируя это отклонение заданным значением (deviation).
 // Если deviation равно 0 (чтобы предотвратить деление на ноль), функция возвращает 0.
 // Это может использоваться для измерения, насколько далеко текущее значение находится от среднего, с учетом типичной изменчивости.
+//
+// Формула: ToPercent((cur - mean) / deviation)
 func DeviancePercent(cur, mean, deviation float64) float64 {
 	if deviation == 0 {
 		return 0
 	}
-	return (cur - mean) / (deviation / 100)
+	return ToPercent((cur - mean) / deviation)
 }
 
 func EmaFormula(price, multiplier, EMAp float64) float64 {
@@ -125,7 +132,7 @@ func EmaMultiplier(period float64) float64 {
 
 // CurFormula вычисляет новое значение на основе начального значения и процента изменения.
 //
-// Формула: pcnt*initialValue/100 + initialValue
+// Формула: ApplyReturn(initialValue, ToFraction(pcnt))
 func CurFormula(initialValue, pcnt float64) float64 {
-	return pcnt*initialValue/100 + initialValue // Применяется для расчёта текущей стоимости с учётом процента изменения
+	return ApplyReturn(initialValue, ToFraction(pcnt)) // Применяется для расчёта текущей стоимости с учётом процента изменения
 }
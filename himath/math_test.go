@@ -243,7 +243,10 @@ func TestCentralDerivative(t *testing.T) {
 	}
 
 	sma := indicator.Sma(130, closing)
-	derivative := CentralDerivative(sma)
+	derivative, err := CentralDerivative(sma)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	for i, j := 10, 1000; i > 0; i, j = i-1, j+1000 {
 		if i > 1 {
@@ -0,0 +1,199 @@
+package himath
+
+import (
+	"fmt"
+	"github.com/AlexanderKolesnkov/golang-utils-stuff/timefh"
+	"math"
+)
+
+// Metrics take per-candle returns as fractions (0.01 is 1%), e.g. PctChange of an equity curve,
+// and the timeframe code of the candles ("1", "5", ..., "D", "W", "M") for annualization.
+
+// PeriodsPerYear returns count of candles of the timeframe in a year, crypto markets trade 24/7
+func PeriodsPerYear(timeframe string) (float64, error) {
+	switch timeframe {
+	case "W":
+		return 365.0 / 7, nil
+	case "M":
+		return 12, nil
+	}
+
+	seconds := timefh.ConvertTimeframeInUnix(timeframe)
+	if seconds <= 0 {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownTimeframe, timeframe)
+	}
+	return 365 * timefh.Day / float64(seconds), nil
+}
+
+// AnnualizedVolatility sample standard deviation of returns scaled to a year
+//
+// Formula: std(returns) * sqrt(periodsPerYear)
+func AnnualizedVolatility(returns []float64, timeframe string) (float64, error) {
+	periods, err := PeriodsPerYear(timeframe)
+	if err != nil {
+		return 0, err
+	}
+	if len(returns) < 2 {
+		return 0, ErrInsufficientData
+	}
+	return math.Sqrt(varianceGeneral(returns) * periods), nil
+}
+
+// SharpeRatio annualized Sharpe ratio, riskFree is an annual rate as a fraction
+//
+// Formula: (mean(returns) - riskFree / periodsPerYear) / std(returns) * sqrt(periodsPerYear)
+func SharpeRatio(returns []float64, riskFree float64, timeframe string) (float64, error) {
+	periods, err := PeriodsPerYear(timeframe)
+	if err != nil {
+		return 0, err
+	}
+	if len(returns) < 2 {
+		return 0, ErrInsufficientData
+	}
+
+	std := math.Sqrt(varianceGeneral(returns))
+	if std == 0 {
+		return 0, nil
+	}
+	return (Mean(returns) - riskFree/periods) / std * math.Sqrt(periods), nil
+}
+
+// SortinoRatio annualized Sortino ratio, only returns below riskFree are counted as risk
+//
+// Formula: (mean(returns) - rf) / sqrt(mean(min(returns - rf, 0)^2)) * sqrt(periodsPerYear)
+func SortinoRatio(returns []float64, riskFree float64, timeframe string) (float64, error) {
+	periods, err := PeriodsPerYear(timeframe)
+	if err != nil {
+		return 0, err
+	}
+	if len(returns) < 2 {
+		return 0, ErrInsufficientData
+	}
+
+	rf := riskFree / periods
+	downside := 0.0
+	for _, r := range returns {
+		if r < rf {
+			downside += (r - rf) * (r - rf)
+		}
+	}
+	downside = math.Sqrt(downside / float64(len(returns)))
+	if downside == 0 {
+		return 0, nil
+	}
+	return (Mean(returns) - rf) / downside * math.Sqrt(periods), nil
+}
+
+// MaxDrawdown returns the biggest fall from a peak of the equity curve as a fraction
+// with indexes of the peak and of the trough.
+//
+// Formula: max((peak - equity) / peak)
+func MaxDrawdown(equity []float64) (drawdown float64, start, end int) {
+	peak := 0
+	for i, v := range equity {
+		if v > equity[peak] {
+			peak = i
+			continue
+		}
+		if equity[peak] == 0 {
+			continue
+		}
+		if dd := (equity[peak] - v) / equity[peak]; dd > drawdown {
+			drawdown, start, end = dd, peak, i
+		}
+	}
+	return drawdown, start, end
+}
+
+// MaxDrawdownDuration returns the longest count of candles the equity stayed below its previous peak.
+// A drawdown that is not recovered until the end of the curve is counted up to the last candle.
+func MaxDrawdownDuration(equity []float64) int {
+	longest, peak := 0, 0
+	for i, v := range equity {
+		if v >= equity[peak] {
+			peak = i
+			continue
+		}
+		if duration := i - peak; duration > longest {
+			longest = duration
+		}
+	}
+	return longest
+}
+
+// CAGR compound annual growth rate of the equity curve
+//
+// Formula: (last / first) ^ (periodsPerYear / (len - 1)) - 1
+func CAGR(equity []float64, timeframe string) (float64, error) {
+	periods, err := PeriodsPerYear(timeframe)
+	if err != nil {
+		return 0, err
+	}
+	if len(equity) < 2 || equity[0] <= 0 {
+		return 0, ErrInsufficientData
+	}
+
+	years := float64(len(equity)-1) / periods
+	return math.Pow(equity[len(equity)-1]/equity[0], 1/years) - 1, nil
+}
+
+// CalmarRatio CAGR per unit of max drawdown, 0 when the curve has no drawdown
+//
+// Formula: CAGR / MaxDrawdown
+func CalmarRatio(equity []float64, timeframe string) (float64, error) {
+	cagr, err := CAGR(equity, timeframe)
+	if err != nil {
+		return 0, err
+	}
+
+	drawdown, _, _ := MaxDrawdown(equity)
+	if drawdown == 0 {
+		return 0, nil
+	}
+	return cagr / drawdown, nil
+}
+
+// WinRate share of trades with positive pnl
+func WinRate(trades []float64) float64 {
+	if len(trades) == 0 {
+		return 0
+	}
+
+	wins := 0
+	for _, pnl := range trades {
+		if pnl > 0 {
+			wins++
+		}
+	}
+	return float64(wins) / float64(len(trades))
+}
+
+// ProfitFactor gross profit divided by gross loss, +Inf when there are profits and no losses
+func ProfitFactor(trades []float64) float64 {
+	profit, loss := 0.0, 0.0
+	for _, pnl := range trades {
+		if pnl > 0 {
+			profit += pnl
+		} else {
+			loss -= pnl
+		}
+	}
+
+	if loss == 0 {
+		if profit == 0 {
+			return 0
+		}
+		return math.Inf(1)
+	}
+	return profit / loss
+}
+
+// Expectancy average pnl per trade
+//
+// Formula: winRate * avgWin - lossRate * avgLoss
+func Expectancy(trades []float64) float64 {
+	if len(trades) == 0 {
+		return 0
+	}
+	return Mean(trades)
+}
@@ -0,0 +1,177 @@
+package himath
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestPeriodsPerYear(t *testing.T) {
+	tests := []struct {
+		timeframe string
+		want      float64
+		wantErr   error
+	}{
+		{timeframe: "1", want: 525600},
+		{timeframe: "5", want: 105120},
+		{timeframe: "60", want: 8760},
+		{timeframe: "240", want: 2190},
+		{timeframe: "D", want: 365},
+		{timeframe: "W", want: 365.0 / 7},
+		{timeframe: "M", want: 12},
+		{timeframe: "3", wantErr: ErrUnknownTimeframe},
+	}
+	for _, tt := range tests {
+		t.Run(tt.timeframe, func(t *testing.T) {
+			got, err := PeriodsPerYear(tt.timeframe)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.InDelta(t, tt.want, got, 1e-9)
+		})
+	}
+}
+
+func TestReturnRatios(t *testing.T) {
+	returns := []float64{0.01, -0.02, 0.015, 0.03, -0.01, 0.005}
+
+	tests := []struct {
+		name    string
+		fn      func() (float64, error)
+		want    float64
+		wantErr error
+	}{
+		{
+			name: "annualized volatility",
+			fn:   func() (float64, error) { return AnnualizedVolatility(returns, "D") },
+			want: 0.34176014981270125,
+		},
+		{
+			name: "sharpe",
+			fn:   func() (float64, error) { return SharpeRatio(returns, 0, "D") },
+			want: 5.340002340823456,
+		},
+		{
+			name: "sharpe with risk free rate",
+			fn:   func() (float64, error) { return SharpeRatio(returns, 0.05, "D") },
+			want: 5.193700906828292,
+		},
+		{
+			name: "sharpe flat returns",
+			fn:   func() (float64, error) { return SharpeRatio([]float64{0.01, 0.01}, 0, "D") },
+			want: 0,
+		},
+		{
+			name: "sortino",
+			fn:   func() (float64, error) { return SortinoRatio(returns, 0, "D") },
+			want: 10.464224768228174,
+		},
+		{
+			name:    "sharpe unknown timeframe",
+			fn:      func() (float64, error) { return SharpeRatio(returns, 0, "2") },
+			wantErr: ErrUnknownTimeframe,
+		},
+		{
+			name:    "sortino single return",
+			fn:      func() (float64, error) { return SortinoRatio([]float64{0.01}, 0, "D") },
+			wantErr: ErrInsufficientData,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.fn()
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.InDelta(t, tt.want, got, 1e-9)
+		})
+	}
+}
+
+func TestDrawdown(t *testing.T) {
+	tests := []struct {
+		name         string
+		equity       []float64
+		wantDrawdown float64
+		wantStart    int
+		wantEnd      int
+		wantDuration int
+	}{
+		{
+			name:         "recovered",
+			equity:       []float64{100, 110, 105, 120, 90, 95, 130, 125},
+			wantDrawdown: 0.25,
+			wantStart:    3,
+			wantEnd:      4,
+			wantDuration: 2,
+		},
+		{
+			name:         "not recovered",
+			equity:       []float64{100, 90, 95, 80, 85},
+			wantDrawdown: 0.2,
+			wantStart:    0,
+			wantEnd:      3,
+			wantDuration: 4,
+		},
+		{
+			name:   "only growth",
+			equity: []float64{100, 101, 102},
+		},
+		{
+			name: "empty",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			drawdown, start, end := MaxDrawdown(tt.equity)
+			assert.InDelta(t, tt.wantDrawdown, drawdown, 1e-9)
+			assert.Equal(t, tt.wantStart, start)
+			assert.Equal(t, tt.wantEnd, end)
+			assert.Equal(t, tt.wantDuration, MaxDrawdownDuration(tt.equity))
+		})
+	}
+}
+
+func TestCAGR(t *testing.T) {
+	got, err := CAGR([]float64{100, 110, 121}, "M")
+	assert.NoError(t, err)
+	assert.InDelta(t, 2.1384283767209995, got, 1e-9)
+
+	got, err = CalmarRatio([]float64{100, 100, 150, 120, 150}, "W")
+	assert.NoError(t, err)
+	assert.InDelta(t, 982.2913808503548, got, 1e-6)
+
+	_, err = CAGR([]float64{100}, "D")
+	assert.ErrorIs(t, err, ErrInsufficientData)
+}
+
+func TestTradeStats(t *testing.T) {
+	tests := []struct {
+		name             string
+		trades           []float64
+		wantWinRate      float64
+		wantProfitFactor float64
+		wantExpectancy   float64
+	}{
+		{
+			name:             "mixed",
+			trades:           []float64{10, -5, 20, -10, 0},
+			wantWinRate:      0.4,
+			wantProfitFactor: 2,
+			wantExpectancy:   3,
+		},
+		{
+			name:             "only wins",
+			trades:           []float64{1, 2},
+			wantWinRate:      1,
+			wantProfitFactor: math.Inf(1),
+			wantExpectancy:   1.5,
+		},
+		{
+			name: "no trades",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.wantWinRate, WinRate(tt.trades), 1e-9)
+			assert.Equal(t, tt.wantProfitFactor, ProfitFactor(tt.trades))
+			assert.InDelta(t, tt.wantExpectancy, Expectancy(tt.trades), 1e-9)
+		})
+	}
+}
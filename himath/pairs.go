@@ -0,0 +1,271 @@
+package himath
+
+import (
+	"gonum.org/v1/gonum/mat"
+	"math"
+)
+
+// HedgeRatio fits y = alpha + beta * x by ordinary least squares,
+// beta is the quantity of x hedging one unit of y
+func HedgeRatio(y, x []float64) (alpha, beta float64, err error) {
+	if len(y) != len(x) {
+		return 0, 0, ErrLengthMismatch
+	}
+	if len(x) < 3 {
+		return 0, 0, ErrInsufficientData
+	}
+
+	fit, err := ols(withIntercept(x), y)
+	if err != nil {
+		return 0, 0, err
+	}
+	return fit.coef[0], fit.coef[1], nil
+}
+
+// withIntercept returns design matrix with the column of ones and x
+func withIntercept(x []float64) *mat.Dense {
+	design := mat.NewDense(len(x), 2, nil)
+	for i, v := range x {
+		design.Set(i, 0, 1)
+		design.Set(i, 1, v)
+	}
+	return design
+}
+
+// RollingHedgeRatio returns beta of HedgeRatio over the last period points,
+// the first period-1 values are NaN
+func RollingHedgeRatio(period int, y, x []float64) ([]float64, error) {
+	if period < 3 {
+		return nil, ErrInvalidPeriod
+	}
+	if len(y) != len(x) {
+		return nil, ErrLengthMismatch
+	}
+
+	output := nanSeries(len(y))
+	var sumX, sumY, sumXX, sumXY float64
+	for i := range y {
+		sumX += x[i]
+		sumY += y[i]
+		sumXX += x[i] * x[i]
+		sumXY += x[i] * y[i]
+		if i >= period {
+			j := i - period
+			sumX -= x[j]
+			sumY -= y[j]
+			sumXX -= x[j] * x[j]
+			sumXY -= x[j] * y[j]
+		}
+		if i < period-1 {
+			continue
+		}
+
+		n := float64(period)
+		if denominator := n*sumXX - sumX*sumX; denominator != 0 {
+			output[i] = (n*sumXY - sumX*sumY) / denominator
+		}
+	}
+	return output, nil
+}
+
+// Spread returns residuals y - alpha - beta * x of the pair
+func Spread(y, x []float64, alpha, beta float64) ([]float64, error) {
+	if len(y) != len(x) {
+		return nil, ErrLengthMismatch
+	}
+
+	spread := make([]float64, len(y))
+	for i := range y {
+		spread[i] = y[i] - alpha - beta*x[i]
+	}
+	return spread, nil
+}
+
+// SpreadZScore returns z-score of the spread against mean and standard deviation of the last lookback points,
+// the first lookback-1 values are NaN and flat window gives 0
+func SpreadZScore(lookback int, spread []float64) ([]float64, error) {
+	if lookback < 2 {
+		return nil, ErrInvalidPeriod
+	}
+
+	output := nanSeries(len(spread))
+	var sum, sumSquares float64
+	for i, v := range spread {
+		sum += v
+		sumSquares += v * v
+		if i >= lookback {
+			old := spread[i-lookback]
+			sum -= old
+			sumSquares -= old * old
+		}
+		if i < lookback-1 {
+			continue
+		}
+
+		n := float64(lookback)
+		mean := sum / n
+		std := math.Sqrt(math.Max(0, sumSquares/n-mean*mean))
+		output[i] = 0
+		if std > 0 {
+			output[i] = (v - mean) / std
+		}
+	}
+	return output, nil
+}
+
+// HalfLife returns the number of bars the spread needs to revert half way to its mean,
+// +Inf when the spread does not revert
+//
+// Regression: Δs[t] = c + λ*s[t-1], half-life = -ln(2) / λ
+func HalfLife(spread []float64) (float64, error) {
+	if len(spread) < 4 {
+		return 0, ErrInsufficientData
+	}
+
+	n := len(spread) - 1
+	diff := make([]float64, n)
+	for i := range diff {
+		diff[i] = spread[i+1] - spread[i]
+	}
+
+	fit, err := ols(withIntercept(spread[:n]), diff)
+	if err != nil {
+		return 0, err
+	}
+
+	lambda := fit.coef[1]
+	if lambda >= 0 {
+		return math.Inf(1), nil
+	}
+	return -math.Ln2 / lambda, nil
+}
+
+// CointegrationResult result of the Engle-Granger test of a pair
+type CointegrationResult struct {
+	ADFResult // unit root test of the spread with critical values for two variables
+
+	Alpha  float64
+	Beta   float64
+	Spread []float64
+}
+
+// EngleGranger two-step cointegration test: fits y = alpha + beta * x and tests the spread for a unit root,
+// small PValue means the pair is cointegrated. Negative lags are chosen as in ADFTest.
+func EngleGranger(y, x []float64, lags int) (CointegrationResult, error) {
+	alpha, beta, err := HedgeRatio(y, x)
+	if err != nil {
+		return CointegrationResult{}, err
+	}
+
+	spread, err := Spread(y, x, alpha, beta)
+	if err != nil {
+		return CointegrationResult{}, err
+	}
+
+	adf, err := unitRootTest(spread, lags, mackinnon[2])
+	if err != nil {
+		return CointegrationResult{}, err
+	}
+	return CointegrationResult{ADFResult: adf, Alpha: alpha, Beta: beta, Spread: spread}, nil
+}
+
+// KalmanHedge dynamic hedge ratio of y = alpha + beta * x estimated by Kalman filter,
+// where alpha and beta follow random walks
+type KalmanHedge struct {
+	transition  float64 // variance of alpha and beta steps
+	observation float64 // variance of the measurement noise
+
+	state [2]float64    // beta, alpha
+	cov   [2][2]float64 // covariance of the state
+	ready bool
+
+	spread   float64
+	variance float64
+}
+
+// NewKalmanHedge returns filter where delta in (0, 1) controls adaptation speed, e.g. 1e-4,
+// and observation is variance of the spread noise, e.g. 1e-3
+func NewKalmanHedge(delta, observation float64) (*KalmanHedge, error) {
+	if delta <= 0 || delta >= 1 || observation <= 0 {
+		return nil, ErrInvalidParameter
+	}
+	return &KalmanHedge{transition: delta / (1 - delta), observation: observation}, nil
+}
+
+// Update feeds the next pair of prices
+func (k *KalmanHedge) Update(y, x float64) {
+	// prediction: state is unchanged, covariance grows by the transition noise
+	r := k.cov
+	r[0][0] += k.transition
+	r[1][1] += k.transition
+
+	h := [2]float64{x, 1}
+	k.spread = y - (h[0]*k.state[0] + h[1]*k.state[1])
+
+	rh := [2]float64{r[0][0]*h[0] + r[0][1]*h[1], r[1][0]*h[0] + r[1][1]*h[1]}
+	k.variance = h[0]*rh[0] + h[1]*rh[1] + k.observation
+
+	gain := [2]float64{rh[0] / k.variance, rh[1] / k.variance}
+	k.state[0] += gain[0] * k.spread
+	k.state[1] += gain[1] * k.spread
+
+	for i := 0; i < 2; i++ {
+		for j := 0; j < 2; j++ {
+			k.cov[i][j] = r[i][j] - gain[i]*rh[j]
+		}
+	}
+	k.ready = true
+}
+
+// Beta returns current hedge ratio
+func (k *KalmanHedge) Beta() float64 {
+	if !k.ready {
+		return math.NaN()
+	}
+	return k.state[0]
+}
+
+// Alpha returns current intercept
+func (k *KalmanHedge) Alpha() float64 {
+	if !k.ready {
+		return math.NaN()
+	}
+	return k.state[1]
+}
+
+// Spread returns forecast error of the last y, it is the tradable spread of the dynamic hedge
+func (k *KalmanHedge) Spread() float64 {
+	if !k.ready {
+		return math.NaN()
+	}
+	return k.spread
+}
+
+// ZScore returns the last spread divided by its forecast standard deviation
+func (k *KalmanHedge) ZScore() float64 {
+	if !k.ready {
+		return math.NaN()
+	}
+	return k.spread / math.Sqrt(k.variance)
+}
+
+// KalmanHedgeRatio runs KalmanHedge over the pair and returns beta, alpha and spread for each point
+func KalmanHedgeRatio(y, x []float64, delta, observation float64) (beta, alpha, spread []float64, err error) {
+	if len(y) != len(x) {
+		return nil, nil, nil, ErrLengthMismatch
+	}
+
+	filter, err := NewKalmanHedge(delta, observation)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	beta = make([]float64, len(y))
+	alpha = make([]float64, len(y))
+	spread = make([]float64, len(y))
+	for i := range y {
+		filter.Update(y[i], x[i])
+		beta[i], alpha[i], spread[i] = filter.Beta(), filter.Alpha(), filter.Spread()
+	}
+	return beta, alpha, spread, nil
+}
@@ -0,0 +1,112 @@
+package himath
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// cointegratedPair returns x as a random walk and y = 10 + 2x + AR(0.5) noise
+func cointegratedPair(n int) (y, x []float64) {
+	rnd := rand.New(rand.NewSource(7))
+	x = make([]float64, n)
+	y = make([]float64, n)
+	x[0] = 100
+	noise := 0.0
+	for i := range x {
+		if i > 0 {
+			x[i] = x[i-1] + rnd.NormFloat64()
+		}
+		noise = 0.5*noise + rnd.NormFloat64()
+		y[i] = 10 + 2*x[i] + noise
+	}
+	return y, x
+}
+
+func TestHedgeRatio(t *testing.T) {
+	x := []float64{1, 2, 3, 4, 5, 6}
+	y := []float64{3, 5, 7, 9, 11, 13}
+
+	alpha, beta, err := HedgeRatio(y, x)
+	assert.NoError(t, err)
+	assert.InDelta(t, 1, alpha, 1e-9)
+	assert.InDelta(t, 2, beta, 1e-9)
+
+	rolling, err := RollingHedgeRatio(3, []float64{2, 4, 6, 9, 12, 15}, x)
+	assert.NoError(t, err)
+	assertSeries(t, []float64{nan, nan, 2, 2.5, 3, 3}, rolling)
+
+	spread, err := Spread([]float64{3, 6, 7}, x[:3], 1, 2)
+	assert.NoError(t, err)
+	assertSeries(t, []float64{0, 1, 0}, spread)
+
+	_, _, err = HedgeRatio(y, x[:2])
+	assert.ErrorIs(t, err, ErrLengthMismatch)
+	_, err = RollingHedgeRatio(2, y, x)
+	assert.ErrorIs(t, err, ErrInvalidPeriod)
+}
+
+func TestSpreadZScore(t *testing.T) {
+	got, err := SpreadZScore(3, []float64{1, 2, 3, 3, 3, 3})
+	assert.NoError(t, err)
+	assertSeries(t, []float64{nan, nan, math.Sqrt(1.5), 1 / math.Sqrt2, 0, 0}, got)
+
+	_, err = SpreadZScore(1, []float64{1, 2})
+	assert.ErrorIs(t, err, ErrInvalidPeriod)
+}
+
+func TestHalfLife(t *testing.T) {
+	// s[t] = 0.5 * s[t-1] without noise gives λ = -0.5
+	spread := []float64{16, 8, 4, 2, 1, 0.5}
+	got, err := HalfLife(spread)
+	assert.NoError(t, err)
+	assert.InDelta(t, 2*math.Ln2, got, 1e-9)
+
+	trending, err := HalfLife([]float64{1, 2, 4, 8, 16})
+	assert.NoError(t, err)
+	assert.True(t, math.IsInf(trending, 1))
+
+	noisy, err := HalfLife(randomSeries(2000, 0.9))
+	assert.NoError(t, err)
+	assert.InDelta(t, -math.Ln2/(0.9-1), noisy, 2)
+}
+
+func TestEngleGranger(t *testing.T) {
+	y, x := cointegratedPair(500)
+
+	got, err := EngleGranger(y, x, 1)
+	assert.NoError(t, err)
+	assert.InDelta(t, 2, got.Beta, 0.05)
+	assert.Len(t, got.Spread, 500)
+	assert.True(t, got.Stationary())
+	assert.Less(t, got.PValue, 0.01)
+	assert.InDelta(t, -3.3483, got.Critical5, 1e-3)
+
+	independent, err := EngleGranger(randomSeries(500, 1), x, 1)
+	assert.NoError(t, err)
+	assert.False(t, independent.Stationary())
+	assert.Greater(t, independent.PValue, 0.05)
+}
+
+func TestKalmanHedgeRatio(t *testing.T) {
+	y, x := cointegratedPair(500)
+
+	beta, alpha, spread, err := KalmanHedgeRatio(y, x, 1e-5, 1)
+	assert.NoError(t, err)
+	assert.Len(t, alpha, 500)
+	assert.InDelta(t, 2, beta[len(beta)-1], 0.15)
+	assert.InDelta(t, 0, Mean(spread[100:]), 0.2)
+
+	filter, err := NewKalmanHedge(1e-5, 1)
+	assert.NoError(t, err)
+	assert.True(t, math.IsNaN(filter.Beta()))
+	filter.Update(y[0], x[0])
+	assert.Equal(t, spread[0], filter.Spread())
+	assert.False(t, math.IsNaN(filter.ZScore()))
+
+	_, err = NewKalmanHedge(1, 1)
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+	_, _, _, err = KalmanHedgeRatio(y, x[:1], 1e-5, 1)
+	assert.ErrorIs(t, err, ErrLengthMismatch)
+}
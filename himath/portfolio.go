@@ -0,0 +1,262 @@
+package himath
+
+import (
+	"fmt"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+	"math"
+)
+
+// Portfolio functions take aligned return series of the assets: series[i][t] is the return of asset i at period t.
+// VaR and CVaR are losses as positive fractions of the portfolio value, e.g. 0.03 is a 3% loss.
+
+// returnsMatrix returns observations by rows and assets by columns
+func returnsMatrix(series [][]float64) (*mat.Dense, error) {
+	if len(series) == 0 {
+		return nil, ErrInsufficientData
+	}
+	if !sameLength(series...) {
+		return nil, ErrLengthMismatch
+	}
+	n := len(series[0])
+	if n < 2 {
+		return nil, ErrInsufficientData
+	}
+
+	m := mat.NewDense(n, len(series), nil)
+	for j, s := range series {
+		m.SetCol(j, s)
+	}
+	return m, nil
+}
+
+// CovarianceMatrix returns sample covariance matrix of the series
+func CovarianceMatrix(series [][]float64) (*mat.SymDense, error) {
+	m, err := returnsMatrix(series)
+	if err != nil {
+		return nil, err
+	}
+	var matrix mat.SymDense
+	stat.CovarianceMatrix(&matrix, m, nil)
+	return &matrix, nil
+}
+
+// CorrelationMatrix returns Pearson correlation matrix of the series, NaN for a series without variance
+func CorrelationMatrix(series [][]float64) (*mat.SymDense, error) {
+	m, err := returnsMatrix(series)
+	if err != nil {
+		return nil, err
+	}
+	var matrix mat.SymDense
+	stat.CorrelationMatrix(&matrix, m, nil)
+	return &matrix, nil
+}
+
+// PortfolioReturns returns per period return of the portfolio of the weights
+//
+// Formula: sum(weights[i] * series[i][t])
+func PortfolioReturns(weights []float64, series [][]float64) ([]float64, error) {
+	if len(weights) != len(series) || !sameLength(series...) {
+		return nil, ErrLengthMismatch
+	}
+	if len(series) == 0 {
+		return nil, ErrInsufficientData
+	}
+
+	returns := make([]float64, len(series[0]))
+	for i, s := range series {
+		for t, r := range s {
+			returns[t] += weights[i] * r
+		}
+	}
+	return returns, nil
+}
+
+// PortfolioVolatility returns standard deviation of the portfolio return per period
+//
+// Formula: sqrt(w' * cov * w)
+func PortfolioVolatility(weights []float64, cov mat.Symmetric) (float64, error) {
+	if len(weights) != cov.SymmetricDim() {
+		return 0, ErrLengthMismatch
+	}
+	w := mat.NewVecDense(len(weights), append([]float64(nil), weights...))
+	return math.Sqrt(mat.Inner(w, cov, w)), nil
+}
+
+// RiskContributions returns share of every asset in the portfolio variance, the shares sum to 1
+//
+// Formula: w[i] * (cov * w)[i] / (w' * cov * w)
+func RiskContributions(weights []float64, cov mat.Symmetric) ([]float64, error) {
+	if len(weights) != cov.SymmetricDim() {
+		return nil, ErrLengthMismatch
+	}
+
+	w := mat.NewVecDense(len(weights), append([]float64(nil), weights...))
+	var marginal mat.VecDense
+	marginal.MulVec(cov, w)
+	variance := mat.Dot(w, &marginal)
+
+	contributions := make([]float64, len(weights))
+	for i := range contributions {
+		contributions[i] = weights[i] * marginal.AtVec(i) / variance
+	}
+	return contributions, nil
+}
+
+// checkConfidence checks the VaR confidence level and returns the returns without NaN
+func checkConfidence(returns []float64, confidence float64) ([]float64, error) {
+	if !(confidence > 0 && confidence < 1) {
+		return nil, ErrInvalidParameter
+	}
+	returns = withoutNaN(returns)
+	if len(returns) < 2 {
+		return nil, ErrInsufficientData
+	}
+	return returns, nil
+}
+
+// HistoricalVaR returns loss not exceeded with the confidence, e.g. 0.95, by the empirical quantile of returns
+//
+// Formula: -quantile(returns, 1 - confidence)
+func HistoricalVaR(returns []float64, confidence float64) (float64, error) {
+	returns, err := checkConfidence(returns, confidence)
+	if err != nil {
+		return 0, err
+	}
+	return -quantile(sortedValues(returns), 1-confidence), nil
+}
+
+// HistoricalCVaR returns expected shortfall: average loss of returns at or below the VaR quantile
+func HistoricalCVaR(returns []float64, confidence float64) (float64, error) {
+	returns, err := checkConfidence(returns, confidence)
+	if err != nil {
+		return 0, err
+	}
+
+	sorted := sortedValues(returns)
+	cutoff := quantile(sorted, 1-confidence)
+	sum, count := 0.0, 0
+	for _, r := range sorted {
+		if r > cutoff {
+			break
+		}
+		sum += r
+		count++
+	}
+	return -sum / float64(count), nil
+}
+
+// ParametricVaR returns VaR of normally distributed returns with the sample mean and standard deviation
+//
+// Formula: -(mean + z * std), z is the standard normal quantile of 1 - confidence
+func ParametricVaR(returns []float64, confidence float64) (float64, error) {
+	returns, err := checkConfidence(returns, confidence)
+	if err != nil {
+		return 0, err
+	}
+
+	z := distuv.UnitNormal.Quantile(1 - confidence)
+	return -(Mean(returns) + z*math.Sqrt(varianceGeneral(returns))), nil
+}
+
+// ParametricCVaR returns expected shortfall of normally distributed returns
+//
+// Formula: -(mean - std * pdf(z) / (1 - confidence))
+func ParametricCVaR(returns []float64, confidence float64) (float64, error) {
+	returns, err := checkConfidence(returns, confidence)
+	if err != nil {
+		return 0, err
+	}
+
+	z := distuv.UnitNormal.Quantile(1 - confidence)
+	std := math.Sqrt(varianceGeneral(returns))
+	return -(Mean(returns) - std*distuv.UnitNormal.Prob(z)/(1-confidence)), nil
+}
+
+// MinVarianceWeights returns fully invested weights with the lowest portfolio variance, short weights are allowed
+//
+// Formula: inv(cov) * 1 / (1' * inv(cov) * 1)
+func MinVarianceWeights(cov mat.Symmetric) ([]float64, error) {
+	n := cov.SymmetricDim()
+	var cholesky mat.Cholesky
+	if !cholesky.Factorize(cov) {
+		return nil, fmt.Errorf("min variance: covariance is not positive definite: %w", ErrInvalidParameter)
+	}
+
+	ones := mat.NewVecDense(n, nil)
+	for i := 0; i < n; i++ {
+		ones.SetVec(i, 1)
+	}
+	var solution mat.VecDense
+	if err := cholesky.SolveVecTo(&solution, ones); err != nil {
+		return nil, fmt.Errorf("min variance: %w", err)
+	}
+
+	total := mat.Sum(&solution)
+	weights := make([]float64, n)
+	for i := range weights {
+		weights[i] = solution.AtVec(i) / total
+	}
+	return weights, nil
+}
+
+// RiskParityWeights returns long only weights summing to 1 where every asset contributes equally
+// to the portfolio variance, found by cyclical coordinate descent
+func RiskParityWeights(cov mat.Symmetric) ([]float64, error) {
+	const (
+		maxIterations = 1000
+		tolerance     = 1e-12
+	)
+
+	n := cov.SymmetricDim()
+	weights := make([]float64, n)
+	for i := range weights {
+		if !(cov.At(i, i) > 0) {
+			return nil, fmt.Errorf("risk parity: variance of asset %d is not positive: %w", i, ErrInvalidParameter)
+		}
+		weights[i] = 1 / math.Sqrt(cov.At(i, i))
+	}
+
+	// every step solves cov[i][i] * w[i]^2 + c * w[i] - 1/n = 0 for w[i], c = sum of cov[i][j] * w[j], j != i
+	budget := 1 / float64(n)
+	for iteration := 0; iteration < maxIterations; iteration++ {
+		change := 0.0
+		for i := range weights {
+			c := 0.0
+			for j, w := range weights {
+				if j != i {
+					c += cov.At(i, j) * w
+				}
+			}
+			w := (-c + math.Sqrt(c*c+4*cov.At(i, i)*budget)) / (2 * cov.At(i, i))
+			change = math.Max(change, math.Abs(w-weights[i]))
+			weights[i] = w
+		}
+		if change < tolerance {
+			break
+		}
+	}
+
+	total := Series(weights).Sum()
+	Series(weights).Scale(1 / total)
+	return weights, nil
+}
+
+// Beta returns sensitivity of returns to the benchmark returns, NaN when the benchmark has no variance
+//
+// Formula: cov(returns, benchmark) / var(benchmark)
+func Beta(returns, benchmark []float64) (float64, error) {
+	if len(returns) != len(benchmark) {
+		return 0, ErrLengthMismatch
+	}
+	if len(returns) < 2 {
+		return 0, ErrInsufficientData
+	}
+
+	variance := stat.Variance(benchmark, nil)
+	if variance == 0 {
+		return math.NaN(), nil
+	}
+	return stat.Covariance(returns, benchmark, nil) / variance, nil
+}
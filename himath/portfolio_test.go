@@ -0,0 +1,155 @@
+package himath
+
+import (
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/gonum/mat"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestCovarianceAndCorrelationMatrix(t *testing.T) {
+	series := [][]float64{{1, 2, 3, 4}, {2, 4, 6, 8}, {4, 3, 1, 2}}
+
+	cov, err := CovarianceMatrix(series)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, cov.SymmetricDim())
+	assert.InDelta(t, 5.0/3, cov.At(0, 0), 1e-12)
+	assert.InDelta(t, 10.0/3, cov.At(0, 1), 1e-12)
+	assert.InDelta(t, -4.0/3, cov.At(0, 2), 1e-12)
+
+	corr, err := CorrelationMatrix(series)
+	assert.NoError(t, err)
+	assert.InDelta(t, 1, corr.At(0, 1), 1e-12)
+	assert.InDelta(t, -0.8, corr.At(0, 2), 1e-12)
+	assert.InDelta(t, 1, corr.At(2, 2), 1e-12)
+
+	_, err = CovarianceMatrix([][]float64{{1, 2}, {1}})
+	assert.ErrorIs(t, err, ErrLengthMismatch)
+	_, err = CorrelationMatrix([][]float64{{1}})
+	assert.ErrorIs(t, err, ErrInsufficientData)
+	_, err = CorrelationMatrix(nil)
+	assert.ErrorIs(t, err, ErrInsufficientData)
+}
+
+func TestPortfolioVolatility(t *testing.T) {
+	cov := mat.NewSymDense(2, []float64{0.04, 0, 0, 0.09})
+	vol, err := PortfolioVolatility([]float64{0.5, 0.5}, cov)
+	assert.NoError(t, err)
+	assert.InDelta(t, math.Sqrt(0.0325), vol, 1e-12)
+
+	_, err = PortfolioVolatility([]float64{1}, cov)
+	assert.ErrorIs(t, err, ErrLengthMismatch)
+
+	rng := rand.New(rand.NewSource(2))
+	series := make([][]float64, 3)
+	for i := range series {
+		series[i] = make([]float64, 100)
+		for t := range series[i] {
+			series[i][t] = rng.NormFloat64() * 0.01 * float64(i+1)
+		}
+	}
+	weights := []float64{0.2, 0.3, 0.5}
+	returns, err := PortfolioReturns(weights, series)
+	assert.NoError(t, err)
+	sampleCov, _ := CovarianceMatrix(series)
+	vol, err = PortfolioVolatility(weights, sampleCov)
+	assert.NoError(t, err)
+	assert.InDelta(t, math.Sqrt(varianceGeneral(returns)), vol, 1e-12)
+
+	_, err = PortfolioReturns([]float64{1}, series)
+	assert.ErrorIs(t, err, ErrLengthMismatch)
+}
+
+func TestValueAtRisk(t *testing.T) {
+	returns := []float64{0.04, -0.05, 0.03, -0.04, 0.02, -0.03, 0.01, -0.02, 0, -0.01, nan}
+
+	v, err := HistoricalVaR(returns, 0.9)
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.041, v, 1e-12)
+
+	cvar, err := HistoricalCVaR(returns, 0.9)
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.05, cvar, 1e-12)
+
+	rng := rand.New(rand.NewSource(4))
+	normal := make([]float64, 100000)
+	for i := range normal {
+		normal[i] = rng.NormFloat64() * 0.02
+	}
+	v, err = ParametricVaR(normal, 0.95)
+	assert.NoError(t, err)
+	assert.InDelta(t, 1.645*0.02, v, 5e-4)
+	cvar, err = ParametricCVaR(normal, 0.95)
+	assert.NoError(t, err)
+	assert.InDelta(t, 2.063*0.02, cvar, 5e-4)
+
+	historical, _ := HistoricalVaR(normal, 0.95)
+	assert.InDelta(t, v, historical, 1e-3)
+	historicalCVaR, _ := HistoricalCVaR(normal, 0.95)
+	assert.InDelta(t, cvar, historicalCVaR, 1e-3)
+
+	_, err = HistoricalVaR(returns, 1)
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+	_, err = ParametricCVaR([]float64{0.1}, 0.95)
+	assert.ErrorIs(t, err, ErrInsufficientData)
+}
+
+func TestPortfolioWeights(t *testing.T) {
+	diagonal := mat.NewSymDense(2, []float64{0.04, 0, 0, 0.01})
+
+	minVariance, err := MinVarianceWeights(diagonal)
+	assert.NoError(t, err)
+	assertSeries(t, []float64{0.2, 0.8}, minVariance)
+
+	parity, err := RiskParityWeights(diagonal)
+	assert.NoError(t, err)
+	assertSeries(t, []float64{1.0 / 3, 2.0 / 3}, parity)
+
+	correlated := mat.NewSymDense(3, []float64{
+		0.04, 0.006, 0.002,
+		0.006, 0.09, 0.009,
+		0.002, 0.009, 0.01,
+	})
+	parity, err = RiskParityWeights(correlated)
+	assert.NoError(t, err)
+	assert.InDelta(t, 1, Series(parity).Sum(), 1e-12)
+	contributions, err := RiskContributions(parity, correlated)
+	assert.NoError(t, err)
+	assertSeries(t, []float64{1.0 / 3, 1.0 / 3, 1.0 / 3}, contributions)
+
+	minVariance, err = MinVarianceWeights(correlated)
+	assert.NoError(t, err)
+	best, _ := PortfolioVolatility(minVariance, correlated)
+	for _, weights := range [][]float64{parity, {1.0 / 3, 1.0 / 3, 1.0 / 3}, {0, 0, 1}} {
+		vol, _ := PortfolioVolatility(weights, correlated)
+		assert.LessOrEqual(t, best, vol)
+	}
+
+	singular := mat.NewSymDense(2, []float64{1, 1, 1, 1})
+	_, err = MinVarianceWeights(singular)
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+	_, err = RiskParityWeights(mat.NewSymDense(2, []float64{0, 0, 0, 1}))
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+}
+
+func TestBeta(t *testing.T) {
+	benchmark := []float64{0.01, -0.02, 0.03, 0.005, -0.01}
+	returns := make([]float64, len(benchmark))
+	for i, r := range benchmark {
+		returns[i] = 2*r + 0.001
+	}
+
+	beta, err := Beta(returns, benchmark)
+	assert.NoError(t, err)
+	assert.InDelta(t, 2, beta, 1e-12)
+
+	flat, err := Beta(returns, []float64{0.01, 0.01, 0.01, 0.01, 0.01})
+	assert.NoError(t, err)
+	assert.True(t, math.IsNaN(flat))
+
+	_, err = Beta(returns, benchmark[:2])
+	assert.ErrorIs(t, err, ErrLengthMismatch)
+	_, err = Beta([]float64{1}, []float64{1})
+	assert.ErrorIs(t, err, ErrInsufficientData)
+}
@@ -0,0 +1,182 @@
+package himath
+
+import (
+	"github.com/AlexanderKolesnkov/golang-utils-stuff/consts"
+	"math"
+)
+
+// MarginMode position margin mode
+type MarginMode int
+
+const (
+	// Isolated only the position margin and the extra margin can be lost
+	Isolated MarginMode = iota
+	// Cross the whole Balance backs the position
+	Cross
+)
+
+// RiskTier maintenance margin tier of a risk limit, as listed in Bybit risk limit table
+type RiskTier struct {
+	Limit                 float64 // max position value of the tier
+	MaintenanceMarginRate float64 // fraction, 0.005 is 0.5%
+	Deduction             float64 // maintenance margin deduction of the tier
+}
+
+// Position linear perpetual position (USDT contracts)
+type Position struct {
+	Side       string // consts.Buy or consts.Sell
+	Qty        float64
+	EntryPrice float64
+	Leverage   float64
+	FeeRate    float64 // taker fee rate, 0.00055 is 0.055%
+
+	Mode        MarginMode
+	Balance     float64 // wallet balance backing a Cross position
+	ExtraMargin float64 // margin added to an Isolated position
+
+	// Tiers sorted by Limit, the position without tiers has no maintenance margin
+	Tiers []RiskTier
+
+	// RealisedPnl closed pnl minus trading fees, the opening fee is charged at once
+	RealisedPnl float64
+}
+
+// NewPosition opens the position and charges the opening fee
+func NewPosition(side string, qty, entryPrice, leverage, feeRate float64) (*Position, error) {
+	if side != consts.Buy && side != consts.Sell {
+		return nil, ErrInvalidSide
+	}
+	if !(qty > 0) {
+		return nil, ErrInvalidQty
+	}
+	if !(entryPrice > 0) {
+		return nil, ErrInvalidPrice
+	}
+	if leverage < 1 {
+		return nil, ErrInvalidLeverage
+	}
+
+	return &Position{
+		Side:        side,
+		Qty:         qty,
+		EntryPrice:  entryPrice,
+		Leverage:    leverage,
+		FeeRate:     feeRate,
+		RealisedPnl: -qty * entryPrice * feeRate,
+	}, nil
+}
+
+// direction returns 1 for long and -1 for short
+func (p *Position) direction() float64 {
+	if p.Side == consts.Sell {
+		return -1
+	}
+	return 1
+}
+
+// Value position value by entry price
+//
+// Formula: qty * entryPrice
+func (p *Position) Value() float64 {
+	return p.Qty * p.EntryPrice
+}
+
+// InitialMargin
+//
+// Formula: qty * entryPrice / leverage
+func (p *Position) InitialMargin() float64 {
+	return CalcInitialMargin(p.Qty, p.EntryPrice, p.Leverage)
+}
+
+// MaintenanceMargin by the risk tier of the position value
+//
+// Formula: value * MMR - deduction
+func (p *Position) MaintenanceMargin() float64 {
+	value := p.Value()
+	for i, tier := range p.Tiers {
+		if value <= tier.Limit || i == len(p.Tiers)-1 {
+			return value*tier.MaintenanceMarginRate - tier.Deduction
+		}
+	}
+	return 0
+}
+
+// margin the amount that can be lost before liquidation
+func (p *Position) margin() float64 {
+	if p.Mode == Cross {
+		return p.Balance
+	}
+	return p.InitialMargin() + p.ExtraMargin
+}
+
+// UnrealisedPnl
+//
+// Formula: (markPrice - entryPrice) * qty, reversed for short
+func (p *Position) UnrealisedPnl(markPrice float64) float64 {
+	return (markPrice - p.EntryPrice) * p.Qty * p.direction()
+}
+
+// ROE return on initial margin in percent
+//
+// Formula: unrealisedPnl / (IM / 100)
+func (p *Position) ROE(markPrice float64) float64 {
+	return CalcPnlPcnt(p.UnrealisedPnl(markPrice), p.InitialMargin())
+}
+
+// LiquidationPrice price where the margin left equals the maintenance margin
+//
+// Formula: entryPrice ∓ (margin - MM) / qty, margin is IM + extra margin for Isolated and Balance for Cross
+func (p *Position) LiquidationPrice() float64 {
+	if p.Qty == 0 {
+		return 0
+	}
+	price := p.EntryPrice - p.direction()*(p.margin()-p.MaintenanceMargin())/p.Qty
+	return math.Max(price, 0)
+}
+
+// BankruptcyPrice price where the whole margin is lost
+//
+// Formula: entryPrice ∓ margin / qty
+func (p *Position) BankruptcyPrice() float64 {
+	if p.Qty == 0 {
+		return 0
+	}
+	price := p.EntryPrice - p.direction()*p.margin()/p.Qty
+	return math.Max(price, 0)
+}
+
+// Add increases the position, the entry price becomes the average by qty
+//
+// Formula: entryPrice = (qty * entryPrice + addQty * price) / (qty + addQty)
+func (p *Position) Add(qty, price float64) error {
+	if !(qty > 0) {
+		return ErrInvalidQty
+	}
+	if !(price > 0) {
+		return ErrInvalidPrice
+	}
+
+	total := p.Qty + qty
+	p.EntryPrice = (p.Qty*p.EntryPrice + qty*price) / total
+	p.Qty = total
+	p.RealisedPnl -= qty * price * p.FeeRate
+
+	return nil
+}
+
+// Close reduces the position by qty at price and returns pnl of the closed part after the fee.
+// Entry price of the remaining part is not changed.
+func (p *Position) Close(qty, price float64) (float64, error) {
+	if !(qty > 0) || qty > p.Qty {
+		return 0, ErrInvalidQty
+	}
+	if !(price > 0) {
+		return 0, ErrInvalidPrice
+	}
+
+	pnl := (price-p.EntryPrice)*qty*p.direction() - qty*price*p.FeeRate
+	p.Qty -= qty
+	p.RealisedPnl += pnl
+
+	return pnl, nil
+}
@@ -0,0 +1,175 @@
+package himath
+
+import (
+	"github.com/AlexanderKolesnkov/golang-utils-stuff/consts"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+// btcTiers first BTCUSDT risk limit tiers on Bybit
+var btcTiers = []RiskTier{
+	{Limit: 2_000_000, MaintenanceMarginRate: 0.005},
+	{Limit: 2_600_000, MaintenanceMarginRate: 0.0056, Deduction: 1200},
+	{Limit: 3_200_000, MaintenanceMarginRate: 0.0062, Deduction: 2760},
+}
+
+func TestPositionLiquidation(t *testing.T) {
+	tests := []struct {
+		name            string
+		side            string
+		qty             float64
+		entryPrice      float64
+		leverage        float64
+		mode            MarginMode
+		balance         float64
+		extraMargin     float64
+		wantIM          float64
+		wantMM          float64
+		wantLiquidation float64
+		wantBankruptcy  float64
+	}{
+		{
+			// Bybit help center: long 1 BTC at 10,000 with 50x
+			name:            "[LONG] Isolated",
+			side:            consts.Buy,
+			qty:             1,
+			entryPrice:      10000,
+			leverage:        50,
+			wantIM:          200,
+			wantMM:          50,
+			wantLiquidation: 9850,
+			wantBankruptcy:  9800,
+		},
+		{
+			name:            "[SHORT] Isolated",
+			side:            consts.Sell,
+			qty:             1,
+			entryPrice:      10000,
+			leverage:        50,
+			wantIM:          200,
+			wantMM:          50,
+			wantLiquidation: 10150,
+			wantBankruptcy:  10200,
+		},
+		{
+			name:            "[LONG] Isolated with extra margin",
+			side:            consts.Buy,
+			qty:             1,
+			entryPrice:      10000,
+			leverage:        50,
+			extraMargin:     100,
+			wantIM:          200,
+			wantMM:          50,
+			wantLiquidation: 9750,
+			wantBankruptcy:  9700,
+		},
+		{
+			name:            "[LONG] Cross",
+			side:            consts.Buy,
+			qty:             2,
+			entryPrice:      10000,
+			leverage:        10,
+			mode:            Cross,
+			balance:         5000,
+			wantIM:          2000,
+			wantMM:          100,
+			wantLiquidation: 7550,
+			wantBankruptcy:  7500,
+		},
+		{
+			name:            "[SHORT] Second tier",
+			side:            consts.Sell,
+			qty:             50,
+			entryPrice:      50000,
+			leverage:        25,
+			wantIM:          100000,
+			wantMM:          12800,
+			wantLiquidation: 51744,
+			wantBankruptcy:  52000,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewPosition(tt.side, tt.qty, tt.entryPrice, tt.leverage, 0)
+			assert.NoError(t, err)
+			p.Mode = tt.mode
+			p.Balance = tt.balance
+			p.ExtraMargin = tt.extraMargin
+			p.Tiers = btcTiers
+
+			assert.InDelta(t, tt.wantIM, p.InitialMargin(), 1e-9)
+			assert.InDelta(t, tt.wantMM, p.MaintenanceMargin(), 1e-9)
+			assert.InDelta(t, tt.wantLiquidation, p.LiquidationPrice(), 1e-9)
+			assert.InDelta(t, tt.wantBankruptcy, p.BankruptcyPrice(), 1e-9)
+		})
+	}
+}
+
+func TestPositionPnl(t *testing.T) {
+	// long 2 BTC at 50,000 with 10x, mark price 55,000
+	long, err := NewPosition(consts.Buy, 2, 50000, 10, 0)
+	assert.NoError(t, err)
+	assert.InDelta(t, 10000, long.UnrealisedPnl(55000), 1e-9)
+	assert.InDelta(t, 100, long.ROE(55000), 1e-9)
+
+	short, err := NewPosition(consts.Sell, 2, 50000, 10, 0)
+	assert.NoError(t, err)
+	assert.InDelta(t, -10000, short.UnrealisedPnl(55000), 1e-9)
+	assert.InDelta(t, -100, short.ROE(55000), 1e-9)
+}
+
+func TestPositionAddAndClose(t *testing.T) {
+	// 0.055% taker fee of Bybit non-VIP account
+	p, err := NewPosition(consts.Buy, 1, 20000, 10, 0.00055)
+	assert.NoError(t, err)
+	assert.InDelta(t, -11, p.RealisedPnl, 1e-9)
+
+	assert.NoError(t, p.Add(1, 22000))
+	assert.InDelta(t, 2, p.Qty, 1e-9)
+	assert.InDelta(t, 21000, p.EntryPrice, 1e-9)
+	assert.InDelta(t, 4200, p.InitialMargin(), 1e-9)
+	assert.InDelta(t, -23.1, p.RealisedPnl, 1e-9)
+
+	pnl, err := p.Close(0.5, 23000)
+	assert.NoError(t, err)
+	assert.InDelta(t, 1000-6.325, pnl, 1e-9)
+	assert.InDelta(t, 1.5, p.Qty, 1e-9)
+	assert.InDelta(t, 21000, p.EntryPrice, 1e-9)
+	assert.InDelta(t, 970.575, p.RealisedPnl, 1e-9)
+
+	_, err = p.Close(2, 23000)
+	assert.ErrorIs(t, err, ErrInvalidQty)
+
+	_, err = p.Close(1.5, 20000)
+	assert.NoError(t, err)
+	assert.InDelta(t, 0, p.Qty, 1e-9)
+	assert.Equal(t, 0.0, p.LiquidationPrice())
+}
+
+func TestNewPositionValidation(t *testing.T) {
+	_, err := NewPosition("Long", 1, 100, 10, 0)
+	assert.ErrorIs(t, err, ErrInvalidSide)
+
+	_, err = NewPosition(consts.Buy, 0, 100, 10, 0)
+	assert.ErrorIs(t, err, ErrInvalidQty)
+
+	_, err = NewPosition(consts.Sell, 1, 100, 0.5, 0)
+	assert.ErrorIs(t, err, ErrInvalidLeverage)
+
+	_, err = NewPosition(consts.Buy, math.NaN(), 100, 10, 0)
+	assert.ErrorIs(t, err, ErrInvalidQty)
+
+	p, _ := NewPosition(consts.Buy, 1, 100, 10, 0)
+	for _, price := range []float64{0, -1, math.NaN()} {
+		_, err = NewPosition(consts.Buy, 1, price, 10, 0)
+		assert.ErrorIs(t, err, ErrInvalidPrice, price)
+		assert.ErrorIs(t, p.Add(1, price), ErrInvalidPrice, price)
+		_, err = p.Close(0.5, price)
+		assert.ErrorIs(t, err, ErrInvalidPrice, price)
+	}
+	assert.ErrorIs(t, p.Add(0, 100), ErrInvalidQty)
+	_, err = p.Close(math.NaN(), 100)
+	assert.ErrorIs(t, err, ErrInvalidQty)
+	assert.Equal(t, 1.0, p.Qty, "rejected calls do not change the position")
+}
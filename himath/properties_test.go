@@ -0,0 +1,294 @@
+package himath
+
+import (
+	"errors"
+	"github.com/AlexanderKolesnkov/golang-utils-stuff/consts"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+)
+
+// specialValues are mixed into arbitrary input to hit edge cases of the numeric contract
+var specialValues = []float64{0, -0.0, 1, -1, math.NaN(), math.Inf(1), math.Inf(-1), math.MaxFloat64, math.SmallestNonzeroFloat64}
+
+// arbitraryInput random series of possibly different lengths with special values, periods and scalars
+type arbitraryInput struct {
+	A, B, C []float64
+	Period  int
+	Lag     int
+	Scalar  float64
+}
+
+func arbitraryValue(rnd *rand.Rand) float64 {
+	switch rnd.Intn(4) {
+	case 0:
+		return specialValues[rnd.Intn(len(specialValues))]
+	case 1:
+		return rnd.NormFloat64()
+	default:
+		return 100 + rnd.Float64()*10
+	}
+}
+
+func arbitrarySeries(rnd *rand.Rand, n int) []float64 {
+	series := make([]float64, n)
+	for i := range series {
+		series[i] = arbitraryValue(rnd)
+	}
+	return series
+}
+
+// Generate implements quick.Generator, every third input has series of different lengths
+func (arbitraryInput) Generate(rnd *rand.Rand, size int) reflect.Value {
+	n := rnd.Intn(size + 1)
+	lengths := [3]int{n, n, n}
+	if rnd.Intn(3) == 0 {
+		for i := range lengths {
+			lengths[i] = rnd.Intn(size + 1)
+		}
+	}
+
+	return reflect.ValueOf(arbitraryInput{
+		A:      arbitrarySeries(rnd, lengths[0]),
+		B:      arbitrarySeries(rnd, lengths[1]),
+		C:      arbitrarySeries(rnd, lengths[2]),
+		Period: rnd.Intn(size+4) - 2,
+		Lag:    rnd.Intn(size+4) - 2,
+		Scalar: arbitraryValue(rnd),
+	})
+}
+
+// checkProperty runs the property over arbitrary input, a panic fails the test with the input
+func checkProperty(t *testing.T, property func(in arbitraryInput) bool) {
+	t.Helper()
+	config := &quick.Config{MaxCount: 500, Rand: rand.New(rand.NewSource(1))}
+	assert.NoError(t, quick.Check(property, config))
+}
+
+// lengthContract reports whether err is ErrLengthMismatch exactly when the series differ in length
+func lengthContract(err error, series ...[]float64) bool {
+	if !sameLength(series...) {
+		return errors.Is(err, ErrLengthMismatch)
+	}
+	return !errors.Is(err, ErrLengthMismatch)
+}
+
+func TestNumericContract(t *testing.T) {
+	assert.True(t, math.IsNaN(Mean(nil)))
+	assert.True(t, math.IsNaN(StandardDeviation(nil, 0)))
+	assert.True(t, math.IsNaN(StandardDeviation([]float64{1, math.NaN()}, Mean([]float64{1, math.NaN()}))))
+	assert.Equal(t, 0.0, StandardDeviation([]float64{5}, 5))
+
+	pct := PctChange([]float64{0, 1, 2})
+	assert.True(t, math.IsNaN(pct[0]))
+	assert.Equal(t, 1.0, pct[1])
+
+	returns, err := LogReturns([]float64{1, 0, 1, math.E})
+	assert.NoError(t, err)
+	assertSeries(t, []float64{nan, nan, 1}, returns)
+	_, err = LogReturns([]float64{1})
+	assert.ErrorIs(t, err, ErrInsufficientData)
+
+	_, err = Correlation([]float64{1, 2}, []float64{1})
+	assert.ErrorIs(t, err, ErrLengthMismatch)
+	correlation, err := Correlation([]float64{1, 2, 3}, []float64{2, 4, 6})
+	assert.NoError(t, err)
+	assertSeries(t, []float64{0, 1, 1}, correlation)
+
+	_, err = CentralDerivative([]float64{1, 2})
+	assert.ErrorIs(t, err, ErrInsufficientData)
+}
+
+func TestPropertyBasicMath(t *testing.T) {
+	checkProperty(t, func(in arbitraryInput) bool {
+		Mean(in.A)
+		StandardDeviation(in.A, Mean(in.A))
+		CalculateStandardDeviation(in.A)
+		ZScore(in.A)
+		PctChange(in.A)
+		Median(in.A)
+		MAD(in.A)
+		Skewness(in.A)
+		Kurtosis(in.A)
+
+		_, err := CentralDerivative(in.A)
+		if (len(in.A) < 3) != errors.Is(err, ErrInsufficientData) {
+			return false
+		}
+		_, err = LogReturns(in.A)
+		if (len(in.A) < 2) != errors.Is(err, ErrInsufficientData) {
+			return false
+		}
+		_, err = Correlation(in.A, in.B)
+		return lengthContract(err, in.A, in.B)
+	})
+}
+
+func TestPropertyIndicators(t *testing.T) {
+	checkProperty(t, func(in arbitraryInput) bool {
+		Sma(in.Period, in.A)
+		Ema(in.Period, in.A)
+		Rsi(in.Period, in.A)
+		Macd(in.Period, in.Lag, in.Period, in.A)
+		BollingerBands(in.Period, in.Scalar, in.A)
+		Vwap(in.Period, in.A, in.B)
+		CrossThreshold(in.A, in.Scalar, ZoneAbove, CrossOptions{Hysteresis: in.Scalar})
+
+		if _, err := CrossLines(in.A, in.B, CrossOptions{}); !lengthContract(err, in.A, in.B) {
+			return false
+		}
+		if _, err := Obv(in.A, in.B); !lengthContract(err, in.A, in.B) {
+			return false
+		}
+		if in.Period > 0 {
+			if _, err := Atr(in.Period, in.A, in.B, in.C); !lengthContract(err, in.A, in.B, in.C) {
+				return false
+			}
+			if _, _, _, err := Adx(in.Period, in.A, in.B, in.C); !lengthContract(err, in.A, in.B, in.C) {
+				return false
+			}
+			if _, _, _, err := KeltnerChannels(in.Period, in.Scalar, in.A, in.B, in.C); !lengthContract(err, in.A, in.B, in.C) {
+				return false
+			}
+			if _, _, _, err := DonchianChannels(in.Period, in.A, in.B); !lengthContract(err, in.A, in.B) {
+				return false
+			}
+			if p, err := NewCandleVolumeProfile(in.Period, in.A, in.B, in.C); err == nil {
+				p.PointOfControl()
+				p.ValueArea(0.7)
+			} else if !lengthContract(err, in.A, in.B, in.C) {
+				return false
+			}
+		}
+		if _, _, _, err := AnchoredVwap(in.Lag, in.Scalar, in.A, in.B); !lengthContract(err, in.A, in.B) {
+			return false
+		}
+
+		if stochastic, err := NewStochasticIndicator(in.Period, in.Lag); err == nil {
+			WarmUpCandles(stochastic, in.A, in.B, in.C)
+			stochastic.D()
+		}
+		return true
+	})
+}
+
+func TestPropertyStatistics(t *testing.T) {
+	checkProperty(t, func(in arbitraryInput) bool {
+		TTest(in.A, in.Scalar)
+		WelchTTest(in.A, in.B)
+		JarqueBera(in.A)
+		ADFTest(in.A, in.Lag)
+		HurstExponent(in.A)
+		Autocorrelation(in.A, in.Lag)
+		PartialAutocorrelation(in.A, in.Lag)
+		SpreadZScore(in.Period, in.A)
+		HalfLife(in.A)
+		MADSpikes(in.A, 3.5)
+		RollingZScoreSpikes(in.Period, in.A, 3)
+		HampelFilter(in.Period, in.A, 3)
+		Derivative(in.A, DerivativeOptions{Scheme: DifferenceScheme(in.Lag % 3), Order: in.Period % 3, Edge: EdgeOneSided})
+		SavitzkyGolay(in.Period, in.Lag, 1, in.A)
+		HodrickPrescott(in.A, in.Scalar)
+		Kalman(in.Scalar, in.Scalar, in.A)
+		ExponentialSmoothing(in.Scalar, in.A)
+		HoltSmoothing(in.Scalar, in.Scalar, in.A)
+		IQRSpikes(in.A, 1.5).Indices()
+
+		for _, tf := range append(consts.GetAllTimeframes(), "unknown") {
+			AnnualizedVolatility(in.A, tf)
+			SharpeRatio(in.A, in.Scalar, tf)
+			SortinoRatio(in.A, in.Scalar, tf)
+			CAGR(in.A, tf)
+			CalmarRatio(in.A, tf)
+		}
+		MaxDrawdown(in.A)
+		MaxDrawdownDuration(in.A)
+		WinRate(in.A)
+		ProfitFactor(in.A)
+		Expectancy(in.A)
+		return true
+	})
+}
+
+func TestPropertyPairs(t *testing.T) {
+	checkProperty(t, func(in arbitraryInput) bool {
+		if _, _, err := HedgeRatio(in.A, in.B); !lengthContract(err, in.A, in.B) {
+			return false
+		}
+		if _, err := Spread(in.A, in.B, in.Scalar, in.Scalar); !lengthContract(err, in.A, in.B) {
+			return false
+		}
+		if _, err := EngleGranger(in.A, in.B, in.Lag); !lengthContract(err, in.A, in.B) {
+			return false
+		}
+		if _, _, _, err := KalmanHedgeRatio(in.A, in.B, 1e-4, 1e-3); !lengthContract(err, in.A, in.B) {
+			return false
+		}
+		if in.Period >= 3 {
+			if _, err := RollingHedgeRatio(in.Period, in.A, in.B); !lengthContract(err, in.A, in.B) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+func TestPropertyPortfolio(t *testing.T) {
+	checkProperty(t, func(in arbitraryInput) bool {
+		series := [][]float64{in.A, in.B}
+		if cov, err := CovarianceMatrix(series); err == nil {
+			PortfolioVolatility([]float64{in.Scalar, 1 - in.Scalar}, cov)
+			MinVarianceWeights(cov)
+			RiskParityWeights(cov)
+		} else if !lengthContract(err, in.A, in.B) {
+			return false
+		}
+		if _, err := PortfolioReturns([]float64{0.5, 0.5}, series); !lengthContract(err, in.A, in.B) {
+			return false
+		}
+		if _, err := Beta(in.A, in.B); !lengthContract(err, in.A, in.B) {
+			return false
+		}
+		HistoricalVaR(in.A, in.Scalar)
+		HistoricalCVaR(in.A, in.Scalar)
+		ParametricVaR(in.A, in.Scalar)
+		ParametricCVaR(in.A, in.Scalar)
+		return true
+	})
+}
+
+func TestPropertyTrading(t *testing.T) {
+	checkProperty(t, func(in arbitraryInput) bool {
+		scalars := append(in.A, in.Scalar, in.Scalar, in.Scalar, in.Scalar)
+		qty, price, leverage, fee := scalars[0], scalars[1], scalars[2], scalars[3]
+
+		CalcStopLossPcnt(price, in.Scalar, leverage, consts.Sell)
+		CalcPnlPcnt(price, in.Scalar)
+		KellyCriterion(fee, leverage)
+
+		if position, err := NewPosition(consts.Buy, qty, price, leverage, fee); err == nil {
+			position.LiquidationPrice()
+			position.BankruptcyPrice()
+			position.ROE(in.Scalar)
+			position.Add(qty, in.Scalar)
+			position.Close(qty, in.Scalar)
+		}
+
+		instrument := Instrument{TickSize: fee, QtyStep: leverage, MinNotional: in.Scalar}
+		instrument.RoundPrice(price)
+		instrument.FloorQty(qty)
+		instrument.ValidateOrder(price, qty)
+		CalcPositionSize(SizingParams{
+			Side: consts.Sell, Equity: price, RiskPcnt: fee, EntryPrice: price, StopLoss: in.Scalar,
+			Leverage: leverage, Instrument: instrument, Mode: Kelly, WinRate: fee, PayoffRatio: qty,
+		})
+
+		decimal := DecimalFromFloat(price)
+		decimal.Div(DecimalFromFloat(in.Scalar), DivisionScale, RoundHalfEven)
+		decimal.RoundToStep(DecimalFromFloat(fee), RoundUp)
+		return true
+	})
+}
@@ -0,0 +1,54 @@
+package himath
+
+import (
+	"fmt"
+	"gonum.org/v1/gonum/mat"
+	"math"
+)
+
+// olsResult ordinary least squares fit of y = X * coef + residuals
+type olsResult struct {
+	coef      []float64
+	stdErr    []float64
+	residuals []float64
+	rss       float64 // residual sum of squares
+}
+
+// ols fits y by columns of x, the intercept must be passed as a column of ones
+func ols(x *mat.Dense, y []float64) (olsResult, error) {
+	n, k := x.Dims()
+	if n != len(y) {
+		return olsResult{}, ErrLengthMismatch
+	}
+	if n <= k {
+		return olsResult{}, ErrInsufficientData
+	}
+
+	var xtx, inv mat.Dense
+	xtx.Mul(x.T(), x)
+	if err := inv.Inverse(&xtx); err != nil {
+		return olsResult{}, fmt.Errorf("ols: %w", err)
+	}
+
+	var xty, beta, fitted mat.VecDense
+	xty.MulVec(x.T(), mat.NewVecDense(n, y))
+	beta.MulVec(&inv, &xty)
+	fitted.MulVec(x, &beta)
+
+	result := olsResult{
+		coef:      make([]float64, k),
+		stdErr:    make([]float64, k),
+		residuals: make([]float64, n),
+	}
+	for i := 0; i < n; i++ {
+		result.residuals[i] = y[i] - fitted.AtVec(i)
+		result.rss += result.residuals[i] * result.residuals[i]
+	}
+
+	sigma2 := result.rss / float64(n-k)
+	for j := 0; j < k; j++ {
+		result.coef[j] = beta.AtVec(j)
+		result.stdErr[j] = math.Sqrt(sigma2 * inv.At(j, j))
+	}
+	return result, nil
+}
@@ -0,0 +1,141 @@
+package himath
+
+import (
+	"github.com/AlexanderKolesnkov/golang-utils-stuff/consts"
+	"math"
+)
+
+// Returns follow one convention: functions take and return fractions (0.01 is 1%) unless the name says Percent,
+// a positive return is a gain and a negative one is a loss. Legacy helpers working in percent
+// (PercentFormula, CurFormula, DeviancePercent, CalcPnlPcnt) are thin wrappers over this module.
+
+// ReturnKind how returns are measured
+type ReturnKind int
+
+const (
+	// Simple return (to - from) / from, returns of one period compound by multiplication
+	Simple ReturnKind = iota
+	// Logarithmic return ln(to / from), returns of consecutive periods add up
+	Logarithmic
+)
+
+// ToPercent converts fraction into percent, 0.05 becomes 5
+func ToPercent(fraction float64) float64 {
+	return fraction * 100
+}
+
+// ToFraction converts percent into fraction, 5 becomes 0.05
+func ToFraction(percent float64) float64 {
+	return percent / 100
+}
+
+// SimpleReturn returns (to - from) / from, NaN when from is zero
+func SimpleReturn(from, to float64) float64 {
+	if from == 0 {
+		return math.NaN()
+	}
+	return (to - from) / from
+}
+
+// LogReturn returns ln(to / from), NaN when either value is not positive
+func LogReturn(from, to float64) float64 {
+	if from <= 0 || to <= 0 {
+		return math.NaN()
+	}
+	return math.Log(to / from)
+}
+
+// SimpleToLog converts simple return into logarithmic one
+func SimpleToLog(r float64) float64 {
+	return math.Log1p(r)
+}
+
+// LogToSimple converts logarithmic return into simple one
+func LogToSimple(r float64) float64 {
+	return math.Expm1(r)
+}
+
+// Returns returns per-period returns of prices of the kind, one value less than prices
+func Returns(prices []float64, kind ReturnKind) ([]float64, error) {
+	if kind == Logarithmic {
+		return LogReturns(prices)
+	}
+	if len(prices) < 2 {
+		return nil, ErrInsufficientData
+	}
+	return PctChange(prices), nil
+}
+
+// CumulativeReturns returns running total return after every period of the same kind as returns:
+// compounded product for Simple and sum for Logarithmic
+func CumulativeReturns(returns []float64, kind ReturnKind) []float64 {
+	cumulative := make([]float64, len(returns))
+	total := 0.0
+	for i, r := range returns {
+		if kind == Logarithmic {
+			total += r
+		} else {
+			total = (1+total)*(1+r) - 1
+		}
+		cumulative[i] = total
+	}
+	return cumulative
+}
+
+// TotalReturn returns total return of the periods of the same kind as returns, 0 for no periods
+func TotalReturn(returns []float64, kind ReturnKind) float64 {
+	if len(returns) == 0 {
+		return 0
+	}
+	return CumulativeReturns(returns, kind)[len(returns)-1]
+}
+
+// Compound returns total simple return of the rate earned for the number of periods
+//
+// Formula: (1 + rate) ^ periods - 1
+func Compound(rate, periods float64) float64 {
+	return math.Pow(1+rate, periods) - 1
+}
+
+// AnnualizeReturn scales return earned over the number of candles of the timeframe to a year:
+// (1 + r) ^ (periodsPerYear / periods) - 1 for Simple, r * periodsPerYear / periods for Logarithmic
+func AnnualizeReturn(r float64, periods int, kind ReturnKind, timeframe string) (float64, error) {
+	perYear, err := PeriodsPerYear(timeframe)
+	if err != nil {
+		return 0, err
+	}
+	if periods < 1 {
+		return 0, ErrInvalidPeriod
+	}
+
+	if kind == Logarithmic {
+		return r * perYear / float64(periods), nil
+	}
+	return Compound(r, perYear/float64(periods)), nil
+}
+
+// ApplyReturn returns value after the simple return, inverse of SimpleReturn
+//
+// Formula: value * (1 + r)
+func ApplyReturn(value, r float64) float64 {
+	return value * (1 + r)
+}
+
+// TargetPrice returns price where the position opened at entryPrice with leverage reaches
+// the return on margin in percent, inverse of CalcStopLossPcnt: negative pcnt gives the stop-loss price
+//
+// Formula: entryPrice * (1 ± pcnt / 100 / leverage), plus for Buy and minus for Sell
+func TargetPrice(entryPrice, pcnt, leverage float64, side string) (float64, error) {
+	if leverage < 1 {
+		return 0, ErrInvalidLeverage
+	}
+
+	move := ToFraction(pcnt) / leverage
+	switch side {
+	case consts.Buy:
+		return ApplyReturn(entryPrice, move), nil
+	case consts.Sell:
+		return ApplyReturn(entryPrice, -move), nil
+	}
+	return 0, ErrInvalidSide
+}
@@ -0,0 +1,104 @@
+package himath
+
+import (
+	"github.com/AlexanderKolesnkov/golang-utils-stuff/consts"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestReturnConversions(t *testing.T) {
+	assert.Equal(t, 5.0, ToPercent(0.05))
+	assert.Equal(t, 0.05, ToFraction(5))
+	assert.InDelta(t, 0.1, SimpleReturn(100, 110), 1e-12)
+	assert.InDelta(t, math.Log(1.1), LogReturn(100, 110), 1e-12)
+	assert.InDelta(t, 0.1, LogToSimple(SimpleToLog(0.1)), 1e-12)
+	assert.InDelta(t, 110, ApplyReturn(100, SimpleReturn(100, 110)), 1e-9)
+
+	assert.True(t, math.IsNaN(SimpleReturn(0, 1)))
+	assert.True(t, math.IsNaN(LogReturn(-1, 1)))
+}
+
+func TestCumulativeReturns(t *testing.T) {
+	prices := []float64{100, 110, 99, 120}
+
+	simple, err := Returns(prices, Simple)
+	assert.NoError(t, err)
+	assertSeries(t, []float64{0.1, -0.01, 0.2}, CumulativeReturns(simple, Simple))
+	assert.InDelta(t, 0.2, TotalReturn(simple, Simple), 1e-12)
+
+	logarithmic, err := Returns(prices, Logarithmic)
+	assert.NoError(t, err)
+	assert.InDelta(t, math.Log(1.2), TotalReturn(logarithmic, Logarithmic), 1e-12)
+	assert.InDelta(t, 0.2, LogToSimple(TotalReturn(logarithmic, Logarithmic)), 1e-12)
+
+	assert.Equal(t, 0.0, TotalReturn(nil, Simple))
+	_, err = Returns(prices[:1], Simple)
+	assert.ErrorIs(t, err, ErrInsufficientData)
+}
+
+func TestCompoundAndAnnualize(t *testing.T) {
+	assert.InDelta(t, 0.21, Compound(0.1, 2), 1e-12)
+
+	// 1% a day compounds to 1.01^365 - 1 in a year
+	yearly, err := AnnualizeReturn(0.01, 1, Simple, "D")
+	assert.NoError(t, err)
+	assert.InDelta(t, math.Pow(1.01, 365)-1, yearly, 1e-9)
+
+	// 10% in 30 days
+	monthly, err := AnnualizeReturn(0.1, 30, Simple, "D")
+	assert.NoError(t, err)
+	assert.InDelta(t, math.Pow(1.1, 365.0/30)-1, monthly, 1e-9)
+
+	logYearly, err := AnnualizeReturn(0.01, 1, Logarithmic, "60")
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.01*365*24, logYearly, 1e-9)
+
+	_, err = AnnualizeReturn(0.01, 1, Simple, "7")
+	assert.ErrorIs(t, err, ErrUnknownTimeframe)
+	_, err = AnnualizeReturn(0.01, 0, Simple, "D")
+	assert.ErrorIs(t, err, ErrInvalidPeriod)
+}
+
+func TestTargetPrice(t *testing.T) {
+	tests := []struct {
+		name     string
+		entry    float64
+		pcnt     float64
+		leverage float64
+		side     string
+		want     float64
+	}{
+		{name: "buy take-profit", entry: 100, pcnt: 50, leverage: 10, side: consts.Buy, want: 105},
+		{name: "buy stop-loss", entry: 100, pcnt: -20, leverage: 10, side: consts.Buy, want: 98},
+		{name: "sell take-profit", entry: 100, pcnt: 50, leverage: 10, side: consts.Sell, want: 95},
+		{name: "sell stop-loss without leverage", entry: 100, pcnt: -5, leverage: 1, side: consts.Sell, want: 105},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := TargetPrice(tt.entry, tt.pcnt, tt.leverage, tt.side)
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.want, got, 1e-9)
+			assert.InDelta(t, tt.pcnt, CalcStopLossPcnt(got, tt.entry, tt.leverage, tt.side), 1e-9)
+		})
+	}
+
+	_, err := TargetPrice(100, 10, 0.5, consts.Buy)
+	assert.ErrorIs(t, err, ErrInvalidLeverage)
+	_, err = TargetPrice(100, 10, 1, "Hold")
+	assert.ErrorIs(t, err, ErrInvalidSide)
+}
+
+func TestLegacyPercentWrappers(t *testing.T) {
+	assert.InDelta(t, 10, PercentFormula(110, 100), 1e-12)
+	assert.InDelta(t, -10, PercentFormula(90, 100), 1e-12)
+	// the sign follows the direction of the change for negative values
+	assert.InDelta(t, 50, PercentFormula(-50, -100), 1e-12)
+	assert.InDelta(t, -50, PercentFormula(-150, -100), 1e-12)
+	assert.Equal(t, 0.0, PercentFormula(1, 0))
+
+	assert.InDelta(t, 110, CurFormula(100, 10), 1e-12)
+	assert.InDelta(t, 150, DeviancePercent(13, 10, 2), 1e-12)
+	assert.Equal(t, 0.0, DeviancePercent(13, 10, 0))
+	assert.InDelta(t, -25, CalcPnlPcnt(-5, 20), 1e-12)
+}
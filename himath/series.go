@@ -0,0 +1,235 @@
+package himath
+
+import (
+	"gonum.org/v1/gonum/floats"
+	"math"
+)
+
+// Series vector of values for hot paths: methods without suffix work in place,
+// methods with To suffix write into dst and return it. Dst is reused when its capacity is enough,
+// otherwise a new slice is allocated, so a buffer kept between calls makes them allocation free.
+// Dst may be the receiver itself.
+type Series []float64
+
+// buffer returns dst resized to n values, allocating only when its capacity is too small
+func buffer(dst Series, n int) Series {
+	if cap(dst) < n {
+		return make(Series, n)
+	}
+	return dst[:n]
+}
+
+// Len returns the number of values
+func (s Series) Len() int {
+	return len(s)
+}
+
+// Sum returns sum of values, 0 for empty series
+func (s Series) Sum() float64 {
+	return floats.Sum(s)
+}
+
+// Mean returns arithmetic mean, NaN for empty series
+func (s Series) Mean() float64 {
+	if len(s) == 0 {
+		return math.NaN()
+	}
+	return floats.Sum(s) / float64(len(s))
+}
+
+// Variance returns population variance like StandardDeviation, NaN for empty series
+func (s Series) Variance() float64 {
+	mean := s.Mean()
+	if math.IsNaN(mean) {
+		return math.NaN()
+	}
+
+	variance := 0.0
+	for _, v := range s {
+		variance += (v - mean) * (v - mean)
+	}
+	return variance / float64(len(s))
+}
+
+// Std returns population standard deviation, NaN for empty series
+func (s Series) Std() float64 {
+	return math.Sqrt(s.Variance())
+}
+
+// Min returns the smallest value, NaN for empty series
+func (s Series) Min() float64 {
+	if len(s) == 0 {
+		return math.NaN()
+	}
+	return floats.Min(s)
+}
+
+// Max returns the largest value, NaN for empty series
+func (s Series) Max() float64 {
+	if len(s) == 0 {
+		return math.NaN()
+	}
+	return floats.Max(s)
+}
+
+// Dot returns sum of products of values
+func (s Series) Dot(other Series) (float64, error) {
+	if len(s) != len(other) {
+		return 0, ErrLengthMismatch
+	}
+	return floats.Dot(s, other), nil
+}
+
+// Add adds other to the series element-wise
+func (s Series) Add(other Series) error {
+	_, err := s.AddTo(s, other)
+	return err
+}
+
+// AddTo writes s + other into dst
+func (s Series) AddTo(dst, other Series) (Series, error) {
+	if len(s) != len(other) {
+		return nil, ErrLengthMismatch
+	}
+	dst = buffer(dst, len(s))
+	floats.AddTo(dst, s, other)
+	return dst, nil
+}
+
+// Sub subtracts other from the series element-wise
+func (s Series) Sub(other Series) error {
+	_, err := s.SubTo(s, other)
+	return err
+}
+
+// SubTo writes s - other into dst
+func (s Series) SubTo(dst, other Series) (Series, error) {
+	if len(s) != len(other) {
+		return nil, ErrLengthMismatch
+	}
+	dst = buffer(dst, len(s))
+	floats.SubTo(dst, s, other)
+	return dst, nil
+}
+
+// Mul multiplies the series by other element-wise
+func (s Series) Mul(other Series) error {
+	_, err := s.MulTo(s, other)
+	return err
+}
+
+// MulTo writes s * other into dst
+func (s Series) MulTo(dst, other Series) (Series, error) {
+	if len(s) != len(other) {
+		return nil, ErrLengthMismatch
+	}
+	dst = buffer(dst, len(s))
+	floats.MulTo(dst, s, other)
+	return dst, nil
+}
+
+// Div divides the series by other element-wise, division by zero follows IEEE 754
+func (s Series) Div(other Series) error {
+	_, err := s.DivTo(s, other)
+	return err
+}
+
+// DivTo writes s / other into dst
+func (s Series) DivTo(dst, other Series) (Series, error) {
+	if len(s) != len(other) {
+		return nil, ErrLengthMismatch
+	}
+	dst = buffer(dst, len(s))
+	floats.DivTo(dst, s, other)
+	return dst, nil
+}
+
+// Scale multiplies every value by c
+func (s Series) Scale(c float64) {
+	floats.Scale(c, s)
+}
+
+// ScaleTo writes s * c into dst
+func (s Series) ScaleTo(dst Series, c float64) Series {
+	dst = buffer(dst, len(s))
+	floats.ScaleTo(dst, c, s)
+	return dst
+}
+
+// AddConst adds c to every value
+func (s Series) AddConst(c float64) {
+	floats.AddConst(c, s)
+}
+
+// CumSum replaces values by their running sum
+func (s Series) CumSum() {
+	floats.CumSum(s, s)
+}
+
+// Shift moves values n positions later (n < 0 moves earlier) and fills the gap with NaN, like pandas shift
+func (s Series) Shift(n int) {
+	s.ShiftTo(s, n)
+}
+
+// ShiftTo writes s shifted by n positions into dst
+func (s Series) ShiftTo(dst Series, n int) Series {
+	length := len(s)
+	dst = buffer(dst, length)
+	if n > length || -n > length {
+		n = length
+	}
+
+	// copy handles overlapping dst and s like memmove
+	switch {
+	case n >= 0:
+		copy(dst[n:], s[:length-n])
+		fill(dst[:n], math.NaN())
+	default:
+		copy(dst[:length+n], s[-n:])
+		fill(dst[length+n:], math.NaN())
+	}
+	return dst
+}
+
+func fill(values []float64, value float64) {
+	for i := range values {
+		values[i] = value
+	}
+}
+
+// DiffTo writes s[i+1] - s[i] into dst, it has one value less than the series
+func (s Series) DiffTo(dst Series) (Series, error) {
+	if len(s) < 2 {
+		return nil, ErrInsufficientData
+	}
+	dst = buffer(dst, len(s)-1)
+	floats.SubTo(dst, s[1:], s[:len(s)-1])
+	return dst, nil
+}
+
+// PctChangeTo writes simple returns like PctChange into dst, it has one value less than the series
+func (s Series) PctChangeTo(dst Series) (Series, error) {
+	if len(s) < 2 {
+		return nil, ErrInsufficientData
+	}
+
+	dst = buffer(dst, len(s)-1)
+	for i := range dst {
+		// s[i+1] is read before dst[i] is written, so dst may be the series itself
+		dst[i] = SimpleReturn(s[i], s[i+1])
+	}
+	return dst, nil
+}
+
+// LogReturnsTo writes logarithmic returns like LogReturns into dst, it has one value less than the series
+func (s Series) LogReturnsTo(dst Series) (Series, error) {
+	if len(s) < 2 {
+		return nil, ErrInsufficientData
+	}
+
+	dst = buffer(dst, len(s)-1)
+	for i := range dst {
+		dst[i] = LogReturn(s[i], s[i+1])
+	}
+	return dst, nil
+}
@@ -0,0 +1,241 @@
+package himath
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestSeriesReductions(t *testing.T) {
+	s := Series{1, 2, 3, 4, 5}
+	assert.Equal(t, 5, s.Len())
+	assert.Equal(t, 15.0, s.Sum())
+	assert.Equal(t, 3.0, s.Mean())
+	assert.InDelta(t, StandardDeviation(s, Mean(s)), s.Std(), 1e-12)
+	assert.Equal(t, 2.0, s.Variance())
+	assert.Equal(t, 1.0, s.Min())
+	assert.Equal(t, 5.0, s.Max())
+
+	dot, err := s.Dot(Series{1, 1, 1, 1, 1})
+	assert.NoError(t, err)
+	assert.Equal(t, 15.0, dot)
+	_, err = s.Dot(Series{1})
+	assert.ErrorIs(t, err, ErrLengthMismatch)
+
+	var empty Series
+	assert.True(t, math.IsNaN(empty.Mean()))
+	assert.True(t, math.IsNaN(empty.Std()))
+	assert.True(t, math.IsNaN(empty.Min()))
+	assert.True(t, math.IsNaN(empty.Max()))
+}
+
+func TestSeriesElementWise(t *testing.T) {
+	a := Series{1, 2, 3}
+	b := Series{4, 5, 6}
+
+	sum, err := a.AddTo(nil, b)
+	assert.NoError(t, err)
+	assert.Equal(t, Series{5, 7, 9}, sum)
+
+	diff, err := b.SubTo(sum[:0], a)
+	assert.NoError(t, err)
+	assert.Equal(t, Series{3, 3, 3}, diff)
+	assert.Equal(t, &sum[0], &diff[0], "buffer must be reused")
+
+	product, err := a.MulTo(nil, b)
+	assert.NoError(t, err)
+	assert.Equal(t, Series{4, 10, 18}, product)
+
+	quotient, err := b.DivTo(nil, Series{2, 0, 3})
+	assert.NoError(t, err)
+	assert.Equal(t, Series{2, math.Inf(1), 2}, quotient)
+
+	c := Series{1, 2, 3}
+	assert.NoError(t, c.Add(b))
+	assert.NoError(t, c.Sub(a))
+	assert.NoError(t, c.Mul(Series{2, 2, 2}))
+	assert.NoError(t, c.Div(Series{4, 5, 6}))
+	assert.Equal(t, Series{2, 2, 2}, c)
+	assert.ErrorIs(t, c.Add(Series{1}), ErrLengthMismatch)
+
+	c.Scale(0.5)
+	c.AddConst(1)
+	assert.Equal(t, Series{2, 2, 2}, c)
+	assert.Equal(t, Series{6, 6, 6}, c.ScaleTo(nil, 3))
+
+	c.CumSum()
+	assert.Equal(t, Series{2, 4, 6}, c)
+}
+
+func TestSeriesShift(t *testing.T) {
+	tests := []struct {
+		name string
+		n    int
+		want Series
+	}{
+		{name: "lag", n: 2, want: Series{nan, nan, 1, 2}},
+		{name: "lead", n: -1, want: Series{2, 3, 4, nan}},
+		{name: "zero", n: 0, want: Series{1, 2, 3, 4}},
+		{name: "beyond length", n: 10, want: Series{nan, nan, nan, nan}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Series{1, 2, 3, 4}
+			assertSeries(t, tt.want, s.ShiftTo(nil, tt.n))
+
+			s.Shift(tt.n)
+			assertSeries(t, tt.want, s)
+		})
+	}
+}
+
+func TestSeriesReturns(t *testing.T) {
+	prices := Series{100, 105, 0, 120}
+
+	pct, err := prices.PctChangeTo(nil)
+	assert.NoError(t, err)
+	assertSeries(t, PctChange(prices), pct)
+	assertSeries(t, baselinePctChange(prices), PctChange(prices))
+
+	diff, err := prices.DiffTo(nil)
+	assert.NoError(t, err)
+	assertSeries(t, []float64{5, -105, 120}, diff)
+
+	logReturns, err := prices.LogReturnsTo(nil)
+	assert.NoError(t, err)
+	want, _ := LogReturns(prices)
+	assertSeries(t, want, logReturns)
+
+	inPlace := Series{100, 110, 99}
+	got, err := inPlace.PctChangeTo(inPlace)
+	assert.NoError(t, err)
+	assertSeries(t, []float64{0.1, -0.1}, got)
+
+	_, err = Series{1}.PctChangeTo(nil)
+	assert.ErrorIs(t, err, ErrInsufficientData)
+}
+
+func TestSeriesAllocationFree(t *testing.T) {
+	prices := benchmarkPrices(1000)
+	dst := make(Series, len(prices))
+
+	allocs := testing.AllocsPerRun(100, func() {
+		prices.Mean()
+		prices.Std()
+		prices.PctChangeTo(dst)
+		prices.LogReturnsTo(dst)
+		prices.ShiftTo(dst, 3)
+		prices.AddTo(dst, prices)
+	})
+	assert.Zero(t, allocs)
+}
+
+func benchmarkPrices(n int) Series {
+	rnd := rand.New(rand.NewSource(1))
+	prices := make(Series, n)
+	prices[0] = 100
+	for i := 1; i < n; i++ {
+		prices[i] = prices[i-1] * (1 + rnd.NormFloat64()*0.01)
+	}
+	return prices
+}
+
+const benchmarkSize = 10000
+
+func BenchmarkMean(b *testing.B) {
+	prices := benchmarkPrices(benchmarkSize)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Mean(prices)
+	}
+}
+
+func BenchmarkSeriesMean(b *testing.B) {
+	prices := benchmarkPrices(benchmarkSize)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		prices.Mean()
+	}
+}
+
+func BenchmarkStandardDeviation(b *testing.B) {
+	prices := benchmarkPrices(benchmarkSize)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		StandardDeviation(prices, Mean(prices))
+	}
+}
+
+func BenchmarkSeriesStd(b *testing.B) {
+	prices := benchmarkPrices(benchmarkSize)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		prices.Std()
+	}
+}
+
+// baselinePctChange PctChange before it was built on Series, kept as the reference of BenchmarkPctChange
+func baselinePctChange(prices []float64) []float64 {
+	var pctChanges []float64
+	for i := 1; i < len(prices); i++ {
+		if prices[i-1] == 0 {
+			pctChanges = append(pctChanges, math.NaN())
+			continue
+		}
+		change := (prices[i] - prices[i-1]) / prices[i-1]
+		pctChanges = append(pctChanges, change)
+	}
+	return pctChanges
+}
+
+func BenchmarkPctChangeBaseline(b *testing.B) {
+	prices := benchmarkPrices(benchmarkSize)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		baselinePctChange(prices)
+	}
+}
+
+func BenchmarkPctChange(b *testing.B) {
+	prices := benchmarkPrices(benchmarkSize)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		PctChange(prices)
+	}
+}
+
+func BenchmarkSeriesPctChangeTo(b *testing.B) {
+	prices := benchmarkPrices(benchmarkSize)
+	dst := make(Series, benchmarkSize)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		prices.PctChangeTo(dst)
+	}
+}
+
+func BenchmarkLogReturns(b *testing.B) {
+	prices := benchmarkPrices(benchmarkSize)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		LogReturns(prices)
+	}
+}
+
+func BenchmarkSeriesLogReturnsTo(b *testing.B) {
+	prices := benchmarkPrices(benchmarkSize)
+	dst := make(Series, benchmarkSize)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		prices.LogReturnsTo(dst)
+	}
+}
@@ -0,0 +1,187 @@
+package himath
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Simulations take *rand.Rand so runs with the same seed are reproducible, e.g. rand.New(rand.NewSource(42)).
+// NaN returns, like the result of PctChange of a zero price, are dropped before sampling.
+
+// BlockBootstrap returns length returns resampled in blocks of consecutive returns starting at random positions.
+// Blocks keep autocorrelation and volatility clusters of the series, blockSize 1 is the ordinary bootstrap.
+// Blocks wrap around the end of the series (circular block bootstrap).
+func BlockBootstrap(rng *rand.Rand, returns []float64, blockSize, length int) ([]float64, error) {
+	if blockSize <= 0 || length < 0 {
+		return nil, ErrInvalidPeriod
+	}
+	returns, err := bootstrapSource(rng, returns)
+	if err != nil {
+		return nil, err
+	}
+	return blockBootstrap(rng, returns, blockSize, make([]float64, length)), nil
+}
+
+// bootstrapSource checks the generator and returns the returns without NaN
+func bootstrapSource(rng *rand.Rand, returns []float64) ([]float64, error) {
+	if rng == nil {
+		return nil, ErrInvalidParameter
+	}
+	returns = withoutNaN(returns)
+	if len(returns) == 0 {
+		return nil, ErrInsufficientData
+	}
+	return returns, nil
+}
+
+// blockBootstrap fills sample with blocks of the returns without NaN
+func blockBootstrap(rng *rand.Rand, returns []float64, blockSize int, sample []float64) []float64 {
+	for i := 0; i < len(sample); {
+		start := rng.Intn(len(returns))
+		for k := 0; k < blockSize && i < len(sample); k, i = k+1, i+1 {
+			sample[i] = returns[(start+k)%len(returns)]
+		}
+	}
+	return sample
+}
+
+// MonteCarloOptions configure MonteCarloEquity
+type MonteCarloOptions struct {
+	Paths int
+	// Length number of returns in a path, 0 means the length of the returns
+	Length int
+	// BlockSize of the bootstrap, 0 means 1
+	BlockSize int
+	// Kind of the returns, Logarithmic returns are added in the exponent
+	Kind ReturnKind
+	// InitialEquity of every path, 0 means 1
+	InitialEquity float64
+}
+
+// MonteCarloEquity returns equity paths compounding block bootstrapped returns,
+// every path starts with the initial equity and has Length + 1 values
+func MonteCarloEquity(rng *rand.Rand, returns []float64, options MonteCarloOptions) ([][]float64, error) {
+	if options.Paths <= 0 || options.Length < 0 || options.BlockSize < 0 {
+		return nil, ErrInvalidPeriod
+	}
+	returns, err := bootstrapSource(rng, returns)
+	if err != nil {
+		return nil, err
+	}
+	if options.Length == 0 {
+		options.Length = len(returns)
+	}
+	if options.BlockSize == 0 {
+		options.BlockSize = 1
+	}
+	if options.InitialEquity == 0 {
+		options.InitialEquity = 1
+	}
+
+	paths := make([][]float64, options.Paths)
+	sample := make([]float64, options.Length)
+	for p := range paths {
+		blockBootstrap(rng, returns, options.BlockSize, sample)
+
+		path := make([]float64, options.Length+1)
+		path[0] = options.InitialEquity
+		for i, r := range sample {
+			if options.Kind == Logarithmic {
+				r = LogToSimple(r)
+			}
+			path[i+1] = ApplyReturn(path[i], r)
+		}
+		paths[p] = path
+	}
+	return paths, nil
+}
+
+// ConfidenceBands returns per step quantiles of paths of equal length: the median and the bounds
+// holding the level share of paths, e.g. 0.9 gives the 5% and the 95% quantiles
+func ConfidenceBands(paths [][]float64, level float64) (lower, median, upper []float64, err error) {
+	if !(level > 0 && level < 1) {
+		return nil, nil, nil, ErrInvalidParameter
+	}
+	if len(paths) == 0 {
+		return nil, nil, nil, ErrInsufficientData
+	}
+	if !sameLength(paths...) {
+		return nil, nil, nil, ErrLengthMismatch
+	}
+
+	n := len(paths[0])
+	lower, median, upper = make([]float64, n), make([]float64, n), make([]float64, n)
+	step := make([]float64, len(paths))
+	tail := (1 - level) / 2
+	for i := 0; i < n; i++ {
+		for p, path := range paths {
+			step[p] = path[i]
+		}
+		sorted := sortedValues(step)
+		lower[i] = quantile(sorted, tail)
+		median[i] = quantile(sorted, 0.5)
+		upper[i] = quantile(sorted, 1-tail)
+	}
+	return lower, median, upper, nil
+}
+
+// ProbabilityOfRuin returns share of simulated paths of trades that lose the ruin fraction of the equity,
+// e.g. 0.5 for a 50% drawdown from the start. Trade outcomes are R multiples: pnl divided by the amount at risk,
+// -1 is a full stop-loss. Every trade risks the risk fraction of the current equity.
+//
+// Formula: equity *= 1 + risk * R, ruin when equity <= 1 - ruin
+func ProbabilityOfRuin(rng *rand.Rand, outcomes []float64, risk, ruin float64, trades, paths int) (float64, error) {
+	if trades <= 0 || paths <= 0 {
+		return 0, ErrInvalidPeriod
+	}
+	if !(risk > 0 && risk <= 1) || !(ruin > 0 && ruin <= 1) {
+		return 0, ErrInvalidParameter
+	}
+	outcomes, err := bootstrapSource(rng, outcomes)
+	if err != nil {
+		return 0, err
+	}
+
+	sample := make([]float64, trades)
+	ruined := 0
+	for p := 0; p < paths; p++ {
+		blockBootstrap(rng, outcomes, 1, sample)
+		equity := 1.0
+		for _, r := range sample {
+			equity *= 1 + risk*r
+			if equity <= 1-ruin {
+				ruined++
+				break
+			}
+		}
+	}
+	return float64(ruined) / float64(paths), nil
+}
+
+// GeometricBrownianMotion returns steps + 1 synthetic prices starting at start with annual drift
+// and annual volatility as fractions, one step is a candle of the timeframe
+//
+// Formula: S[t+1] = S[t] * exp((drift - volatility^2 / 2) * dt + volatility * sqrt(dt) * Z), dt = 1 / periodsPerYear
+func GeometricBrownianMotion(rng *rand.Rand, start, drift, volatility float64, steps int, timeframe string) ([]float64, error) {
+	if rng == nil || !(start > 0) || !(volatility >= 0) || math.IsNaN(drift) {
+		return nil, ErrInvalidParameter
+	}
+	if steps < 0 {
+		return nil, ErrInvalidPeriod
+	}
+	periods, err := PeriodsPerYear(timeframe)
+	if err != nil {
+		return nil, err
+	}
+
+	dt := 1 / periods
+	mu := (drift - volatility*volatility/2) * dt
+	sigma := volatility * math.Sqrt(dt)
+
+	prices := make([]float64, steps+1)
+	prices[0] = start
+	for i := 1; i <= steps; i++ {
+		prices[i] = prices[i-1] * math.Exp(mu+sigma*rng.NormFloat64())
+	}
+	return prices, nil
+}
@@ -0,0 +1,130 @@
+package himath
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestBlockBootstrap(t *testing.T) {
+	returns := []float64{1, 2, 3, 4, 5, nan}
+
+	first, err := BlockBootstrap(rand.New(rand.NewSource(7)), returns, 2, 20)
+	assert.NoError(t, err)
+	second, _ := BlockBootstrap(rand.New(rand.NewSource(7)), returns, 2, 20)
+	assert.Equal(t, first, second, "the same seed gives the same sample")
+	assert.Len(t, first, 20)
+
+	for _, r := range first {
+		assert.Contains(t, returns[:5], r)
+	}
+	for i := 0; i < len(first); i += 2 {
+		assert.Equal(t, math.Mod(first[i], 5)+1, first[i+1], "blocks keep consecutive returns and wrap around")
+	}
+
+	_, err = BlockBootstrap(nil, returns, 1, 1)
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+	_, err = BlockBootstrap(rand.New(rand.NewSource(1)), returns, 0, 1)
+	assert.ErrorIs(t, err, ErrInvalidPeriod)
+	_, err = BlockBootstrap(rand.New(rand.NewSource(1)), []float64{nan}, 1, 1)
+	assert.ErrorIs(t, err, ErrInsufficientData)
+}
+
+func TestMonteCarloEquity(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	paths, err := MonteCarloEquity(rng, []float64{0.1}, MonteCarloOptions{Paths: 3, Length: 2, InitialEquity: 100})
+	assert.NoError(t, err)
+	assert.Len(t, paths, 3)
+	for _, path := range paths {
+		assertSeries(t, []float64{100, 110, 121}, path)
+	}
+
+	logPaths, err := MonteCarloEquity(rng, []float64{math.Log(1.1)}, MonteCarloOptions{Paths: 1, Kind: Logarithmic})
+	assert.NoError(t, err)
+	assertSeries(t, []float64{1, 1.1}, logPaths[0])
+
+	returns := []float64{0.02, -0.01, 0.015, -0.02, 0.01, nan}
+	first, err := MonteCarloEquity(rand.New(rand.NewSource(3)), returns, MonteCarloOptions{Paths: 50, BlockSize: 2})
+	assert.NoError(t, err)
+	second, _ := MonteCarloEquity(rand.New(rand.NewSource(3)), returns, MonteCarloOptions{Paths: 50, BlockSize: 2})
+	assert.Equal(t, first, second)
+	assert.Len(t, first[0], 6, "length defaults to count of returns without NaN")
+
+	_, err = MonteCarloEquity(rng, returns, MonteCarloOptions{})
+	assert.ErrorIs(t, err, ErrInvalidPeriod)
+	_, err = MonteCarloEquity(nil, returns, MonteCarloOptions{Paths: 1})
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+}
+
+func TestConfidenceBands(t *testing.T) {
+	lower, median, upper, err := ConfidenceBands([][]float64{{1, 1}, {1, 2}, {1, 3}}, 0.5)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1, 1.5}, lower)
+	assert.Equal(t, []float64{1, 2}, median)
+	assert.Equal(t, []float64{1, 2.5}, upper)
+
+	_, _, _, err = ConfidenceBands([][]float64{{1}, {1, 2}}, 0.9)
+	assert.ErrorIs(t, err, ErrLengthMismatch)
+	_, _, _, err = ConfidenceBands(nil, 0.9)
+	assert.ErrorIs(t, err, ErrInsufficientData)
+	_, _, _, err = ConfidenceBands([][]float64{{1}}, 1)
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+}
+
+func TestProbabilityOfRuin(t *testing.T) {
+	rng := rand.New(rand.NewSource(5))
+	tests := []struct {
+		name     string
+		outcomes []float64
+		trades   int
+		want     float64
+	}{
+		// 0.9^7 is the first equity below 0.5
+		{name: "losing streak reaches ruin", outcomes: []float64{-1}, trades: 7, want: 1},
+		{name: "losing streak too short", outcomes: []float64{-1}, trades: 6, want: 0},
+		{name: "only wins", outcomes: []float64{1, 2}, trades: 100, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := ProbabilityOfRuin(rng, tt.outcomes, 0.1, 0.5, tt.trades, 100)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, p)
+		})
+	}
+
+	coinFlip := []float64{-1, 1}
+	careful, err := ProbabilityOfRuin(rand.New(rand.NewSource(5)), coinFlip, 0.01, 0.5, 200, 2000)
+	assert.NoError(t, err)
+	reckless, err := ProbabilityOfRuin(rand.New(rand.NewSource(5)), coinFlip, 0.2, 0.5, 200, 2000)
+	assert.NoError(t, err)
+	assert.Less(t, careful, reckless)
+	assert.Greater(t, reckless, 0.5)
+
+	_, err = ProbabilityOfRuin(rng, coinFlip, 0, 0.5, 10, 10)
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+	_, err = ProbabilityOfRuin(rng, coinFlip, 0.1, 0.5, 0, 10)
+	assert.ErrorIs(t, err, ErrInvalidPeriod)
+}
+
+func TestGeometricBrownianMotion(t *testing.T) {
+	flat, err := GeometricBrownianMotion(rand.New(rand.NewSource(1)), 100, 0.1, 0, 365, "D")
+	assert.NoError(t, err)
+	assert.Len(t, flat, 366)
+	assert.InDelta(t, 100*math.Exp(0.1), flat[365], 1e-9, "zero volatility grows by the drift")
+
+	prices, err := GeometricBrownianMotion(rand.New(rand.NewSource(1)), 100, 0, 0.5, 20000, "D")
+	assert.NoError(t, err)
+	again, _ := GeometricBrownianMotion(rand.New(rand.NewSource(1)), 100, 0, 0.5, 20000, "D")
+	assert.Equal(t, prices, again)
+
+	returns, _ := LogReturns(prices)
+	volatility, err := AnnualizedVolatility(returns, "D")
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.5, volatility, 0.02)
+
+	_, err = GeometricBrownianMotion(rand.New(rand.NewSource(1)), 0, 0, 0.5, 10, "D")
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+	_, err = GeometricBrownianMotion(rand.New(rand.NewSource(1)), 100, 0, 0.5, 10, "7")
+	assert.ErrorIs(t, err, ErrUnknownTimeframe)
+}
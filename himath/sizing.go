@@ -0,0 +1,155 @@
+package himath
+
+import (
+	"github.com/AlexanderKolesnkov/golang-utils-stuff/consts"
+	"math"
+)
+
+// SizingMode how much of the equity is risked per trade
+type SizingMode int
+
+const (
+	// FixedFractional risks RiskPcnt of the equity
+	FixedFractional SizingMode = iota
+	// Kelly risks Kelly criterion fraction of the equity, capped by RiskPcnt when it is set
+	Kelly
+)
+
+// SizingParams input of CalcPositionSize
+type SizingParams struct {
+	Side       string // consts.Buy or consts.Sell
+	Equity     float64
+	RiskPcnt   float64 // percent of equity lost at stop-loss, 1 is 1%
+	EntryPrice float64
+	StopLoss   float64
+	Leverage   float64
+	FeeRate    float64 // taker fee rate paid on entry and on stop-loss
+	Instrument Instrument
+
+	// MaintenanceMarginRate used to check liquidation against stop-loss, 0.005 is 0.5%
+	MaintenanceMarginRate float64
+
+	Mode          SizingMode
+	WinRate       float64 // Kelly: share of winning trades, 0.55 is 55%
+	PayoffRatio   float64 // Kelly: average win / average loss
+	KellyFraction float64 // Kelly: part of the full Kelly to use, 0 means 1
+}
+
+// Sizing result of CalcPositionSize
+type Sizing struct {
+	Qty              float64
+	StopLoss         float64 // stop-loss rounded to tick size away from entry
+	Notional         float64 // qty * entryPrice
+	Margin           float64 // initial margin required
+	Risk             float64 // money lost at stop-loss including fees
+	RiskPcnt         float64 // Risk in percent of equity
+	StopLossPcnt     float64 // CalcStopLossPcnt of the stop-loss
+	LiquidationPrice float64
+}
+
+// KellyCriterion optimal fraction of equity to risk
+//
+// Formula: winRate - (1 - winRate) / payoffRatio
+func KellyCriterion(winRate, payoffRatio float64) float64 {
+	if payoffRatio <= 0 {
+		return 0
+	}
+	return winRate - (1-winRate)/payoffRatio
+}
+
+// riskFraction fraction of equity to lose at stop-loss for the sizing mode
+func (params SizingParams) riskFraction() (float64, error) {
+	fixed := params.RiskPcnt / 100
+	if params.Mode != Kelly {
+		if fixed <= 0 {
+			return 0, ErrNoEdge
+		}
+		return fixed, nil
+	}
+
+	fraction := params.KellyFraction
+	if fraction == 0 {
+		fraction = 1
+	}
+
+	kelly := KellyCriterion(params.WinRate, params.PayoffRatio) * fraction
+	if kelly <= 0 {
+		return 0, ErrNoEdge
+	}
+	if fixed > 0 {
+		return math.Min(kelly, fixed), nil
+	}
+	return kelly, nil
+}
+
+// CalcPositionSize calculate order qty so the loss at stop-loss is the risked part of equity
+//
+// Formula: qty = equity * riskFraction / (|entry - stopLoss| + (entry + stopLoss) * feeRate)
+//
+// Qty is floored to the lot size, the order is rejected when it is below instrument minimums,
+// when its margin exceeds the equity or when liquidation price is reached before stop-loss.
+func CalcPositionSize(params SizingParams) (Sizing, error) {
+	if params.Side != consts.Buy && params.Side != consts.Sell {
+		return Sizing{}, ErrInvalidSide
+	}
+	if params.Leverage < 1 {
+		return Sizing{}, ErrInvalidLeverage
+	}
+	if !(params.EntryPrice > 0) || !(params.StopLoss > 0) || math.IsInf(params.StopLoss, 0) {
+		return Sizing{}, ErrInvalidStopLoss
+	}
+
+	stopLoss := params.StopLoss
+	if params.Side == consts.Buy {
+		stopLoss = params.Instrument.RoundPriceDown(stopLoss)
+		if stopLoss >= params.EntryPrice {
+			return Sizing{}, ErrInvalidStopLoss
+		}
+	} else {
+		stopLoss = params.Instrument.RoundPriceUp(stopLoss)
+		if stopLoss <= params.EntryPrice {
+			return Sizing{}, ErrInvalidStopLoss
+		}
+	}
+
+	fraction, err := params.riskFraction()
+	if err != nil {
+		return Sizing{}, err
+	}
+
+	lossPerUnit := math.Abs(params.EntryPrice-stopLoss) + (params.EntryPrice+stopLoss)*params.FeeRate
+	qty := params.Instrument.FloorQty(params.Equity * fraction / lossPerUnit)
+
+	notional := qty * params.EntryPrice
+	if !(qty > 0) || qty < params.Instrument.MinQty || notional < params.Instrument.MinNotional {
+		return Sizing{}, ErrBelowMinimum
+	}
+
+	margin := CalcInitialMargin(qty, params.EntryPrice, params.Leverage)
+	if margin > params.Equity {
+		return Sizing{}, ErrInsufficientMargin
+	}
+
+	position, err := NewPosition(params.Side, qty, params.EntryPrice, params.Leverage, 0)
+	if err != nil {
+		return Sizing{}, err
+	}
+	position.Tiers = []RiskTier{{Limit: math.Inf(1), MaintenanceMarginRate: params.MaintenanceMarginRate}}
+
+	liquidation := position.LiquidationPrice()
+	if (params.Side == consts.Buy && liquidation >= stopLoss) || (params.Side == consts.Sell && liquidation <= stopLoss) {
+		return Sizing{}, ErrLiquidationBeforeStop
+	}
+
+	risk := qty * lossPerUnit
+	return Sizing{
+		Qty:              qty,
+		StopLoss:         stopLoss,
+		Notional:         notional,
+		Margin:           margin,
+		Risk:             risk,
+		RiskPcnt:         risk / (params.Equity / 100),
+		StopLossPcnt:     CalcStopLossPcnt(stopLoss, params.EntryPrice, params.Leverage, params.Side),
+		LiquidationPrice: liquidation,
+	}, nil
+}
@@ -0,0 +1,150 @@
+package himath
+
+import (
+	"github.com/AlexanderKolesnkov/golang-utils-stuff/consts"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestCalcPositionSize(t *testing.T) {
+	instrument := Instrument{TickSize: 0.05, QtyStep: 0.01, MinQty: 0.01, MinNotional: 5}
+
+	tests := []struct {
+		name     string
+		params   SizingParams
+		wantQty  float64
+		wantStop float64
+		wantRisk float64
+		wantErr  error
+	}{
+		{
+			name: "[LONG] Fixed fractional",
+			params: SizingParams{
+				Side: consts.Buy, Equity: 10000, RiskPcnt: 1, EntryPrice: 100, StopLoss: 95,
+				Leverage: 10, Instrument: instrument, MaintenanceMarginRate: 0.005,
+			},
+			wantQty:  20,
+			wantStop: 95,
+			wantRisk: 100,
+		},
+		{
+			name: "[LONG] Fees reduce qty",
+			params: SizingParams{
+				Side: consts.Buy, Equity: 10000, RiskPcnt: 1, EntryPrice: 100, StopLoss: 95,
+				Leverage: 10, FeeRate: 0.001, Instrument: instrument, MaintenanceMarginRate: 0.005,
+			},
+			wantQty:  19.24,
+			wantStop: 95,
+			wantRisk: 99.9518,
+		},
+		{
+			name: "[SHORT] Stop-loss rounded up to tick",
+			params: SizingParams{
+				Side: consts.Sell, Equity: 10000, RiskPcnt: 1, EntryPrice: 100, StopLoss: 104.03,
+				Leverage: 10, Instrument: instrument, MaintenanceMarginRate: 0.005,
+			},
+			wantQty:  24.69,
+			wantStop: 104.05,
+			wantRisk: 99.9945,
+		},
+		{
+			name: "[LONG] Half Kelly",
+			params: SizingParams{
+				Side: consts.Buy, Equity: 10000, EntryPrice: 100, StopLoss: 90, Leverage: 5,
+				Instrument: instrument, MaintenanceMarginRate: 0.005,
+				Mode: Kelly, WinRate: 0.55, PayoffRatio: 1.5, KellyFraction: 0.5,
+			},
+			wantQty:  125,
+			wantStop: 90,
+			wantRisk: 1250,
+		},
+		{
+			name: "[LONG] Kelly capped by risk pcnt",
+			params: SizingParams{
+				Side: consts.Buy, Equity: 10000, RiskPcnt: 2, EntryPrice: 100, StopLoss: 90, Leverage: 5,
+				Instrument: instrument, MaintenanceMarginRate: 0.005,
+				Mode: Kelly, WinRate: 0.55, PayoffRatio: 1.5,
+			},
+			wantQty:  20,
+			wantStop: 90,
+			wantRisk: 200,
+		},
+		{
+			name: "Kelly without edge",
+			params: SizingParams{
+				Side: consts.Buy, Equity: 10000, EntryPrice: 100, StopLoss: 90, Leverage: 5,
+				Mode: Kelly, WinRate: 0.3, PayoffRatio: 1,
+			},
+			wantErr: ErrNoEdge,
+		},
+		{
+			name: "[LONG] Liquidation before stop-loss",
+			params: SizingParams{
+				Side: consts.Buy, Equity: 10000, RiskPcnt: 1, EntryPrice: 100, StopLoss: 97,
+				Leverage: 50, Instrument: instrument, MaintenanceMarginRate: 0.005,
+			},
+			wantErr: ErrLiquidationBeforeStop,
+		},
+		{
+			name: "Margin exceeds equity",
+			params: SizingParams{
+				Side: consts.Buy, Equity: 1000, RiskPcnt: 5, EntryPrice: 100, StopLoss: 99.9,
+				Leverage: 1, Instrument: instrument,
+			},
+			wantErr: ErrInsufficientMargin,
+		},
+		{
+			name: "Below min notional",
+			params: SizingParams{
+				Side: consts.Buy, Equity: 10, RiskPcnt: 1, EntryPrice: 100, StopLoss: 50,
+				Leverage: 10, Instrument: instrument,
+			},
+			wantErr: ErrBelowMinimum,
+		},
+		{
+			name: "[SHORT] Stop-loss below entry",
+			params: SizingParams{
+				Side: consts.Sell, Equity: 10000, RiskPcnt: 1, EntryPrice: 100, StopLoss: 95, Leverage: 10,
+			},
+			wantErr: ErrInvalidStopLoss,
+		},
+		{
+			name: "NaN stop-loss",
+			params: SizingParams{
+				Side: consts.Buy, Equity: 10000, RiskPcnt: 1, EntryPrice: 100, StopLoss: math.NaN(),
+				Leverage: 10, Instrument: instrument,
+			},
+			wantErr: ErrInvalidStopLoss,
+		},
+		{
+			name: "NaN equity",
+			params: SizingParams{
+				Side: consts.Buy, Equity: math.NaN(), RiskPcnt: 1, EntryPrice: 100, StopLoss: 90,
+				Leverage: 10, Instrument: instrument,
+			},
+			wantErr: ErrBelowMinimum,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CalcPositionSize(tt.params)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.wantQty, got.Qty, 1e-9)
+			assert.InDelta(t, tt.wantStop, got.StopLoss, 1e-9)
+			assert.InDelta(t, tt.wantRisk, got.Risk, 1e-6)
+			assert.InDelta(t, tt.wantRisk/tt.params.Equity*100, got.RiskPcnt, 1e-6)
+			assert.InDelta(t, got.Qty*tt.params.EntryPrice/tt.params.Leverage, got.Margin, 1e-9)
+		})
+	}
+}
+
+func TestKellyCriterion(t *testing.T) {
+	assert.InDelta(t, 0.25, KellyCriterion(0.55, 1.5), 1e-9)
+	assert.InDelta(t, 0, KellyCriterion(0.5, 1), 1e-9)
+	assert.Equal(t, 0.0, KellyCriterion(0.5, 0))
+}
@@ -0,0 +1,245 @@
+package himath
+
+import (
+	"fmt"
+	"gonum.org/v1/gonum/mat"
+	"math"
+)
+
+// savitzkyGolayWeights returns weights of the window values giving the deriv-th derivative
+// of the polyOrder polynomial fitted to the window, evaluated at offset pos from the window center
+func savitzkyGolayWeights(window, polyOrder, deriv int, pos float64) ([]float64, error) {
+	half := window / 2
+
+	// design matrix of powers of offsets -half..half
+	a := mat.NewDense(window, polyOrder+1, nil)
+	for i := 0; i < window; i++ {
+		for j := 0; j <= polyOrder; j++ {
+			a.Set(i, j, math.Pow(float64(i-half), float64(j)))
+		}
+	}
+
+	var ata, inv, projection mat.Dense
+	ata.Mul(a.T(), a)
+	if err := inv.Inverse(&ata); err != nil {
+		return nil, fmt.Errorf("savitzky-golay: %w", err)
+	}
+	projection.Mul(&inv, a.T()) // polynomial coefficients = projection * window values
+
+	// basis of the deriv-th derivative at pos: j!/(j-deriv)! * pos^(j-deriv)
+	basis := make([]float64, polyOrder+1)
+	for j := deriv; j <= polyOrder; j++ {
+		factor := 1.0
+		for k := j - deriv + 1; k <= j; k++ {
+			factor *= float64(k)
+		}
+		basis[j] = factor * math.Pow(pos, float64(j-deriv))
+	}
+
+	weights := make([]float64, window)
+	for i := range weights {
+		for j := range basis {
+			weights[i] += basis[j] * projection.At(j, i)
+		}
+	}
+	return weights, nil
+}
+
+// SavitzkyGolay smooths values by least squares polynomial of polyOrder fitted to the moving odd window,
+// deriv > 0 returns derivative of the fitted polynomial per point, e.g. deriv 1 gives a smoothed trend slope.
+// Edges are evaluated on the polynomial fitted to the first and the last window, so the length is kept.
+func SavitzkyGolay(window, polyOrder, deriv int, values []float64) ([]float64, error) {
+	if window < 3 || window%2 == 0 {
+		return nil, ErrInvalidPeriod
+	}
+	if polyOrder < 0 || polyOrder >= window || deriv < 0 {
+		return nil, ErrInvalidParameter
+	}
+	n := len(values)
+	if n < window {
+		return nil, ErrInsufficientData
+	}
+
+	output := make([]float64, n)
+	if deriv > polyOrder {
+		return output, nil
+	}
+
+	half := window / 2
+	apply := func(weights []float64, start int) float64 {
+		sum := 0.0
+		for k, w := range weights {
+			sum += w * values[start+k]
+		}
+		return sum
+	}
+
+	center, err := savitzkyGolayWeights(window, polyOrder, deriv, 0)
+	if err != nil {
+		return nil, err
+	}
+	for i := half; i < n-half; i++ {
+		output[i] = apply(center, i-half)
+	}
+
+	for i := 0; i < half; i++ {
+		first, err := savitzkyGolayWeights(window, polyOrder, deriv, float64(i-half))
+		if err != nil {
+			return nil, err
+		}
+		last, err := savitzkyGolayWeights(window, polyOrder, deriv, float64(half-i))
+		if err != nil {
+			return nil, err
+		}
+		output[i] = apply(first, 0)
+		output[n-1-i] = apply(last, n-window)
+	}
+	return output, nil
+}
+
+// HodrickPrescott splits values into smooth trend and cycle = values - trend.
+// Lambda penalises curvature of the trend: 0 returns values as trend, infinity approaches a straight line.
+//
+// Trend minimises Σ (y[t] - τ[t])^2 + λ Σ (τ[t+1] - 2τ[t] + τ[t-1])^2, solved as (I + λ D'D) τ = y.
+func HodrickPrescott(values []float64, lambda float64) (trend, cycle []float64, err error) {
+	if !(lambda >= 0) || math.IsInf(lambda, 1) {
+		return nil, nil, ErrInvalidParameter
+	}
+	n := len(values)
+	if n < 3 {
+		return nil, nil, ErrInsufficientData
+	}
+
+	// band storage of the symmetric pentadiagonal matrix: row i keeps A[i][i], A[i][i+1], A[i][i+2]
+	const bandwidth = 2
+	band := make([]float64, n*(bandwidth+1))
+	for i := 0; i < n; i++ {
+		band[i*(bandwidth+1)] = 1
+	}
+	second := [3]float64{1, -2, 1}
+	for r := 0; r+2 < n; r++ {
+		for a := 0; a < 3; a++ {
+			for b := a; b < 3; b++ {
+				band[(r+a)*(bandwidth+1)+b-a] += lambda * second[a] * second[b]
+			}
+		}
+	}
+
+	var cholesky mat.BandCholesky
+	if !cholesky.Factorize(mat.NewSymBandDense(n, bandwidth, band)) {
+		return nil, nil, fmt.Errorf("hodrick-prescott: %w", ErrInvalidParameter)
+	}
+
+	var solution mat.VecDense
+	if err := cholesky.SolveVecTo(&solution, mat.NewVecDense(n, append([]float64(nil), values...))); err != nil {
+		return nil, nil, fmt.Errorf("hodrick-prescott: %w", err)
+	}
+
+	trend = make([]float64, n)
+	cycle = make([]float64, n)
+	for i := range trend {
+		trend[i] = solution.AtVec(i)
+		cycle[i] = values[i] - trend[i]
+	}
+	return trend, cycle, nil
+}
+
+// KalmanFilter one-dimensional Kalman filter of a random walk level observed with noise.
+// It implements Indicator, Value is the filtered level.
+type KalmanFilter struct {
+	process     float64 // variance of the level step between observations
+	measurement float64 // variance of the observation noise
+
+	estimate float64
+	errorCov float64
+	ready    bool
+}
+
+// NewKalmanFilter returns filter, the smaller process variance relative to measurement variance,
+// the smoother and more lagging the level
+func NewKalmanFilter(processVariance, measurementVariance float64) (*KalmanFilter, error) {
+	if !(processVariance >= 0) || !(measurementVariance > 0) {
+		return nil, ErrInvalidParameter
+	}
+	return &KalmanFilter{process: processVariance, measurement: measurementVariance}, nil
+}
+
+// Update feeds the next observation, NaN observations are skipped
+func (k *KalmanFilter) Update(value float64) {
+	if math.IsNaN(value) {
+		return
+	}
+	if !k.ready {
+		k.estimate, k.errorCov, k.ready = value, k.measurement, true
+		return
+	}
+
+	k.errorCov += k.process
+	gain := k.errorCov / (k.errorCov + k.measurement)
+	k.estimate += gain * (value - k.estimate)
+	k.errorCov *= 1 - gain
+}
+
+// Value returns the filtered level
+func (k *KalmanFilter) Value() float64 {
+	if !k.ready {
+		return math.NaN()
+	}
+	return k.estimate
+}
+
+// Ready reports whether the filter has seen an observation
+func (k *KalmanFilter) Ready() bool {
+	return k.ready
+}
+
+// Kalman returns values filtered by KalmanFilter
+func Kalman(processVariance, measurementVariance float64, values []float64) ([]float64, error) {
+	filter, err := NewKalmanFilter(processVariance, measurementVariance)
+	if err != nil {
+		return nil, err
+	}
+	return streamSeries(filter, values), nil
+}
+
+// ExponentialSmoothing simple exponential smoothing seeded with the first value, alpha in (0, 1]
+//
+// Formula: s[t] = alpha * y[t] + (1 - alpha) * s[t-1]
+func ExponentialSmoothing(alpha float64, values []float64) ([]float64, error) {
+	if !(alpha > 0 && alpha <= 1) {
+		return nil, ErrInvalidParameter
+	}
+
+	output := make([]float64, len(values))
+	for i, v := range values {
+		if i == 0 {
+			output[i] = v
+			continue
+		}
+		output[i] = EmaFormula(v, alpha, output[i-1])
+	}
+	return output, nil
+}
+
+// HoltSmoothing double exponential smoothing with level and trend, trend is the smoothed slope per point.
+// Alpha and beta in (0, 1] smooth level and trend, the trend is seeded with the first difference.
+//
+// Formula: level[t] = alpha * y[t] + (1 - alpha) * (level[t-1] + trend[t-1]),
+// trend[t] = beta * (level[t] - level[t-1]) + (1 - beta) * trend[t-1]
+func HoltSmoothing(alpha, beta float64, values []float64) (level, trend []float64, err error) {
+	if !(alpha > 0 && alpha <= 1) || !(beta > 0 && beta <= 1) {
+		return nil, nil, ErrInvalidParameter
+	}
+	if len(values) < 2 {
+		return nil, nil, ErrInsufficientData
+	}
+
+	level = make([]float64, len(values))
+	trend = make([]float64, len(values))
+	level[0], trend[0] = values[0], values[1]-values[0]
+	for i := 1; i < len(values); i++ {
+		level[i] = alpha*values[i] + (1-alpha)*(level[i-1]+trend[i-1])
+		trend[i] = beta*(level[i]-level[i-1]) + (1-beta)*trend[i-1]
+	}
+	return level, trend, nil
+}
@@ -0,0 +1,96 @@
+package himath
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestSavitzkyGolay(t *testing.T) {
+	impulse, err := SavitzkyGolay(5, 2, 0, []float64{0, 0, 0, 0, 1, 0, 0, 0, 0})
+	assert.NoError(t, err)
+	// classic 5 points quadratic coefficients (-3, 12, 17, 12, -3) / 35
+	assertSeries(t, []float64{-3.0 / 35, 12.0 / 35, 17.0 / 35, 12.0 / 35, -3.0 / 35}, impulse[2:7])
+
+	quadratic := make([]float64, 8)
+	slope := make([]float64, 8)
+	for i := range quadratic {
+		x := float64(i)
+		quadratic[i] = 3 + 2*x - 0.5*x*x
+		slope[i] = 2 - x
+	}
+
+	smooth, err := SavitzkyGolay(5, 2, 0, quadratic)
+	assert.NoError(t, err)
+	assertSeries(t, quadratic, smooth)
+
+	derivative, err := SavitzkyGolay(5, 2, 1, quadratic)
+	assert.NoError(t, err)
+	assertSeries(t, slope, derivative)
+
+	_, err = SavitzkyGolay(4, 2, 0, quadratic)
+	assert.ErrorIs(t, err, ErrInvalidPeriod)
+	_, err = SavitzkyGolay(5, 5, 0, quadratic)
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+	_, err = SavitzkyGolay(9, 2, 0, quadratic)
+	assert.ErrorIs(t, err, ErrInsufficientData)
+}
+
+func TestHodrickPrescott(t *testing.T) {
+	line := []float64{1, 3, 5, 7, 9, 11}
+	trend, cycle, err := HodrickPrescott(line, 1600)
+	assert.NoError(t, err)
+	assertSeries(t, line, trend)
+	assertSeries(t, make([]float64, len(line)), cycle)
+
+	zigzag := []float64{0, 1, 0, 1, 0, 1, 0, 1}
+	trend, cycle, err = HodrickPrescott(zigzag, 1e6)
+	assert.NoError(t, err)
+	for i := range zigzag {
+		// huge lambda approaches least squares line 1/3 + x/21
+		assert.InDelta(t, 1.0/3+float64(i)/21, trend[i], 1e-4, "index %d", i)
+		assert.InDelta(t, zigzag[i], trend[i]+cycle[i], 1e-12)
+	}
+
+	unchanged, _, err := HodrickPrescott(zigzag, 0)
+	assert.NoError(t, err)
+	assertSeries(t, zigzag, unchanged)
+
+	_, _, err = HodrickPrescott(zigzag, -1)
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+	_, _, err = HodrickPrescott(zigzag[:2], 1)
+	assert.ErrorIs(t, err, ErrInsufficientData)
+}
+
+func TestKalmanFilter(t *testing.T) {
+	// without process noise the filtered level is the running mean
+	got, err := Kalman(0, 1, []float64{1, 3, 5, math.NaN(), 7})
+	assert.NoError(t, err)
+	assertSeries(t, []float64{1, 2, 3, 3, 4}, got)
+
+	filter, err := NewKalmanFilter(1e-3, 1)
+	assert.NoError(t, err)
+	assert.False(t, filter.Ready())
+	assert.True(t, math.IsNaN(filter.Value()))
+	WarmUp(filter, []float64{10, 10, 10})
+	assert.Equal(t, 10.0, filter.Value())
+
+	_, err = NewKalmanFilter(1, 0)
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+}
+
+func TestExponentialSmoothing(t *testing.T) {
+	got, err := ExponentialSmoothing(0.5, []float64{1, 3, 4, 8})
+	assert.NoError(t, err)
+	assertSeries(t, []float64{1, 2, 3, 5.5}, got)
+
+	level, trend, err := HoltSmoothing(0.5, 0.5, []float64{1, 3, 4, 8})
+	assert.NoError(t, err)
+	assertSeries(t, []float64{1, 3, 4.5, 7.125}, level)
+	assertSeries(t, []float64{2, 2, 1.75, 2.1875}, trend)
+
+	_, err = ExponentialSmoothing(0, []float64{1})
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+	_, _, err = HoltSmoothing(0.5, 0.5, []float64{1})
+	assert.ErrorIs(t, err, ErrInsufficientData)
+}
@@ -0,0 +1,197 @@
+package himath
+
+import (
+	"math"
+	"sort"
+)
+
+// madScale makes MAD a consistent estimator of the standard deviation for normal distribution
+const madScale = 1.4826
+
+// Spikes result of a spike detector: score of every value and mask of flagged indices.
+// NaN values get NaN score and are never flagged.
+type Spikes struct {
+	Scores []float64
+	Mask   []bool
+}
+
+// newSpikes returns result with NaN scores and empty mask
+func newSpikes(n int) Spikes {
+	return Spikes{Scores: nanSeries(n), Mask: make([]bool, n)}
+}
+
+// Indices returns indices of flagged values
+func (s Spikes) Indices() []int {
+	var indices []int
+	for i, flagged := range s.Mask {
+		if flagged {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+// flag sets score of the value and flags it when |score| exceeds the threshold
+func (s Spikes) flag(i int, score, threshold float64) {
+	s.Scores[i] = score
+	s.Mask[i] = math.Abs(score) > threshold
+}
+
+// withoutNaN returns copy of values without NaN
+func withoutNaN(values []float64) []float64 {
+	output := make([]float64, 0, len(values))
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			output = append(output, v)
+		}
+	}
+	return output
+}
+
+// sortedValues returns sorted copy of values without NaN
+func sortedValues(values []float64) []float64 {
+	sorted := withoutNaN(values)
+	sort.Float64s(sorted)
+	return sorted
+}
+
+// quantile returns q quantile of sorted values with linear interpolation between closest ranks
+func quantile(sorted []float64, q float64) float64 {
+	if len(sorted) == 0 {
+		return math.NaN()
+	}
+
+	h := q * float64(len(sorted)-1)
+	lower := int(math.Floor(h))
+	if lower >= len(sorted)-1 {
+		return sorted[len(sorted)-1]
+	}
+	return sorted[lower] + (h-float64(lower))*(sorted[lower+1]-sorted[lower])
+}
+
+// Median returns median of values ignoring NaN, NaN for empty values
+func Median(values []float64) float64 {
+	return quantile(sortedValues(values), 0.5)
+}
+
+// MAD returns median absolute deviation from the median and the median itself, NaN values are ignored
+func MAD(values []float64) (mad, median float64) {
+	median = Median(values)
+	deviations := make([]float64, 0, len(values))
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			deviations = append(deviations, math.Abs(v-median))
+		}
+	}
+	return Median(deviations), median
+}
+
+// robustScore returns deviation from the median in scaled MAD units,
+// ±Inf when MAD is zero and the value differs from the median
+func robustScore(value, median, mad float64) float64 {
+	deviation := value - median
+	if deviation == 0 {
+		return 0
+	}
+	return deviation / (madScale * mad)
+}
+
+// MADSpikes flags values deviating from the median by more than threshold scaled MADs, e.g. 3.5.
+// Unlike ZScore one huge wick does not shift the median and MAD.
+//
+// Formula: score = (x - median) / (1.4826 * MAD)
+func MADSpikes(values []float64, threshold float64) Spikes {
+	spikes := newSpikes(len(values))
+	mad, median := MAD(values)
+	for i, v := range values {
+		if !math.IsNaN(v) {
+			spikes.flag(i, robustScore(v, median, mad), threshold)
+		}
+	}
+	return spikes
+}
+
+// RollingZScoreSpikes flags values deviating from mean of the previous window values
+// by more than threshold standard deviations. The current value is excluded from the window,
+// so the spike does not dampen its own score. The first window values are not scored.
+func RollingZScoreSpikes(window int, values []float64, threshold float64) (Spikes, error) {
+	if window < 2 {
+		return Spikes{}, ErrInvalidPeriod
+	}
+
+	spikes := newSpikes(len(values))
+	for i := window; i < len(values); i++ {
+		if math.IsNaN(values[i]) {
+			continue
+		}
+
+		previous := withoutNaN(values[i-window : i])
+		if len(previous) < 2 {
+			continue
+		}
+
+		mean := Mean(previous)
+		std := StandardDeviation(previous, mean)
+		score := 0.0
+		if deviation := values[i] - mean; deviation != 0 {
+			score = deviation / std
+		}
+		spikes.flag(i, score, threshold)
+	}
+	return spikes, nil
+}
+
+// HampelFilter flags values deviating from the median of the centered window of 2*halfWindow+1 values
+// by more than threshold scaled MADs, e.g. 3, and returns values with spikes replaced by that median.
+// Windows are truncated at the edges of the series.
+func HampelFilter(halfWindow int, values []float64, threshold float64) ([]float64, Spikes, error) {
+	if halfWindow < 1 {
+		return nil, Spikes{}, ErrInvalidPeriod
+	}
+
+	filtered := make([]float64, len(values))
+	copy(filtered, values)
+
+	spikes := newSpikes(len(values))
+	for i, v := range values {
+		if math.IsNaN(v) {
+			continue
+		}
+
+		start, end := max(0, i-halfWindow), min(len(values), i+halfWindow+1)
+		mad, median := MAD(values[start:end])
+		spikes.flag(i, robustScore(v, median, mad), threshold)
+		if spikes.Mask[i] {
+			filtered[i] = median
+		}
+	}
+	return filtered, spikes, nil
+}
+
+// IQRSpikes flags values outside of Tukey fences [Q1 - k*IQR, Q3 + k*IQR], k is usually 1.5.
+// Score is the distance beyond the nearest fence in IQR units, 0 inside the fences.
+func IQRSpikes(values []float64, k float64) Spikes {
+	spikes := newSpikes(len(values))
+
+	sorted := sortedValues(values)
+	q1, q3 := quantile(sorted, 0.25), quantile(sorted, 0.75)
+	iqr := q3 - q1
+	lower, upper := q1-k*iqr, q3+k*iqr
+
+	for i, v := range values {
+		if math.IsNaN(v) {
+			continue
+		}
+
+		score := 0.0
+		switch {
+		case v > upper:
+			score = (v - upper) / iqr
+		case v < lower:
+			score = (v - lower) / iqr
+		}
+		spikes.Scores[i] = score
+		spikes.Mask[i] = v > upper || v < lower
+	}
+	return spikes
+}
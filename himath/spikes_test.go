@@ -0,0 +1,67 @@
+package himath
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+// wick series with a single bad tick at index 5
+var wick = []float64{10, 10.5, 9.5, 10.2, 9.8, 50, 10.1, 9.9}
+
+func TestMAD(t *testing.T) {
+	mad, median := MAD(wick)
+	assert.InDelta(t, 0.2, mad, 1e-12)
+	assert.InDelta(t, 10.05, median, 1e-12)
+
+	assert.Equal(t, 2.0, Median([]float64{3, math.NaN(), 1, 2}))
+	assert.True(t, math.IsNaN(Median(nil)))
+}
+
+func TestMADSpikes(t *testing.T) {
+	got := MADSpikes(wick, 3.5)
+	assertSeries(t, []float64{
+		-0.16862268986915105, 1.5176042088223356, -1.8548495885606378, 0.5058680696074412,
+		-0.8431134493457433, 134.72952920544978, 0.16862268986914508, -0.5058680696074472,
+	}, got.Scores)
+	assert.Equal(t, []int{5}, got.Indices())
+
+	flat := MADSpikes([]float64{1, 1, 1, 2, math.NaN()}, 3.5)
+	assert.Equal(t, []bool{false, false, false, true, false}, flat.Mask)
+	assert.True(t, math.IsInf(flat.Scores[3], 1))
+	assert.True(t, math.IsNaN(flat.Scores[4]))
+}
+
+func TestRollingZScoreSpikes(t *testing.T) {
+	got, err := RollingZScoreSpikes(3, []float64{1, 2, 3, 2, 20, 2}, 3)
+	assert.NoError(t, err)
+	assertSeries(t, []float64{nan, nan, nan, 0, 37.47665940288702, -0.7667775966576988}, got.Scores)
+	assert.Equal(t, []int{4}, got.Indices())
+
+	_, err = RollingZScoreSpikes(1, wick, 3)
+	assert.ErrorIs(t, err, ErrInvalidPeriod)
+}
+
+func TestHampelFilter(t *testing.T) {
+	filtered, spikes, err := HampelFilter(2, wick, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{5}, spikes.Indices())
+	assert.InDelta(t, 134.560907, spikes.Scores[5], 1e-6)
+	assert.InDelta(t, -1.686227, spikes.Scores[2], 1e-6)
+	assert.Equal(t, []float64{10, 10.5, 9.5, 10.2, 9.8, 10.1, 10.1, 9.9}, filtered)
+	assert.Equal(t, 50.0, wick[5])
+
+	_, _, err = HampelFilter(0, wick, 3)
+	assert.ErrorIs(t, err, ErrInvalidPeriod)
+}
+
+func TestIQRSpikes(t *testing.T) {
+	got := IQRSpikes(wick, 1.5)
+	assert.Equal(t, []int{5}, got.Indices())
+	assert.InDelta(t, 97.8125, got.Scores[5], 1e-9)
+	assert.Equal(t, 0.0, got.Scores[0])
+
+	low := IQRSpikes([]float64{1, 2, 3, 4, -20}, 1.5)
+	assert.Equal(t, []int{4}, low.Indices())
+	assert.Less(t, low.Scores[4], 0.0)
+}
@@ -0,0 +1,328 @@
+package himath
+
+import (
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+	"math"
+)
+
+// TestResult result of a hypothesis test, the null hypothesis is rejected when PValue is small
+type TestResult struct {
+	Statistic float64
+	PValue    float64
+	DF        float64 // degrees of freedom of the test distribution
+}
+
+// studentTwoSided two-sided p-value of Student's t statistic
+func studentTwoSided(t, df float64) float64 {
+	return 2 * distuv.StudentsT{Mu: 0, Sigma: 1, Nu: df}.Survival(math.Abs(t))
+}
+
+// TTest one-sample Student's t-test that mean of the sample equals mu, e.g. mu = 0 for returns
+//
+// Formula: t = (mean - mu) / (std / sqrt(n)), df = n - 1
+func TTest(sample []float64, mu float64) (TestResult, error) {
+	n := float64(len(sample))
+	if n < 2 {
+		return TestResult{}, ErrInsufficientData
+	}
+
+	mean, std := stat.MeanStdDev(sample, nil)
+	t := (mean - mu) / (std / math.Sqrt(n))
+	return TestResult{Statistic: t, PValue: studentTwoSided(t, n-1), DF: n - 1}, nil
+}
+
+// WelchTTest two-sample t-test that means are equal without assuming equal variances
+//
+// Formula: t = (meanA - meanB) / sqrt(varA/nA + varB/nB), df by Welch–Satterthwaite
+func WelchTTest(a, b []float64) (TestResult, error) {
+	na, nb := float64(len(a)), float64(len(b))
+	if na < 2 || nb < 2 {
+		return TestResult{}, ErrInsufficientData
+	}
+
+	meanA, varA := stat.MeanVariance(a, nil)
+	meanB, varB := stat.MeanVariance(b, nil)
+	sa, sb := varA/na, varB/nb
+
+	t := (meanA - meanB) / math.Sqrt(sa+sb)
+	df := (sa + sb) * (sa + sb) / (sa*sa/(na-1) + sb*sb/(nb-1))
+	return TestResult{Statistic: t, PValue: studentTwoSided(t, df), DF: df}, nil
+}
+
+// Skewness sample skewness adjusted for the sample size
+func Skewness(sample []float64) float64 {
+	return stat.Skew(sample, nil)
+}
+
+// Kurtosis sample excess kurtosis adjusted for the sample size, 0 for normal distribution
+func Kurtosis(sample []float64) float64 {
+	return stat.ExKurtosis(sample, nil)
+}
+
+// JarqueBera normality test, small PValue means returns are not normally distributed
+//
+// Formula: JB = n / 6 * (S^2 + (K - 3)^2 / 4), JB ~ chi2(2)
+func JarqueBera(sample []float64) (TestResult, error) {
+	n := float64(len(sample))
+	if n < 3 {
+		return TestResult{}, ErrInsufficientData
+	}
+
+	mean := Mean(sample)
+	var m2, m3, m4 float64
+	for _, v := range sample {
+		d := v - mean
+		m2 += d * d
+		m3 += d * d * d
+		m4 += d * d * d * d
+	}
+	m2, m3, m4 = m2/n, m3/n, m4/n
+	if m2 == 0 {
+		return TestResult{}, ErrInsufficientData
+	}
+
+	s := m3 / math.Pow(m2, 1.5)
+	k := m4 / (m2 * m2)
+	jb := n / 6 * (s*s + (k-3)*(k-3)/4)
+
+	return TestResult{Statistic: jb, PValue: distuv.ChiSquared{K: 2}.Survival(jb), DF: 2}, nil
+}
+
+// ADFResult result of the augmented Dickey-Fuller test with a constant
+type ADFResult struct {
+	Statistic float64
+	PValue    float64 // MacKinnon (1994) approximation
+	Lags      int
+	NObs      int // observations used in the regression
+
+	// MacKinnon (2010) critical values for NObs
+	Critical1  float64
+	Critical5  float64
+	Critical10 float64
+}
+
+// Stationary reports whether the unit root is rejected at the 5% level
+func (r ADFResult) Stationary() bool {
+	return r.Statistic < r.Critical5
+}
+
+// mackinnonCritical returns critical value of the response surface b0 + b1/T + b2/T^2 + b3/T^3
+func mackinnonCritical(nobs float64, b [4]float64) float64 {
+	return b[0] + b[1]/nobs + b[2]/(nobs*nobs) + b[3]/(nobs*nobs*nobs)
+}
+
+// mackinnonTable MacKinnon coefficients of the unit root test with a constant for the number of variables
+type mackinnonTable struct {
+	// critical values response surfaces for 1%, 5% and 10% (MacKinnon 2010)
+	critical [3][4]float64
+	// p-value approximation bounds and polynomials (MacKinnon 1994)
+	tauMax, tauMin, tauStar float64
+	smallP, largeP          []float64
+}
+
+// mackinnon tables by the number of variables: 1 for ADF test, 2 for Engle-Granger test of a pair
+var mackinnon = map[int]mackinnonTable{
+	1: {
+		critical: [3][4]float64{
+			{-3.43035, -6.5393, -16.786, -79.433},
+			{-2.86154, -2.8903, -4.234, -40.040},
+			{-2.56677, -1.5384, -2.809, 0},
+		},
+		tauMax: 2.74, tauMin: -18.83, tauStar: -1.61,
+		smallP: []float64{2.1659, 1.4412, 0.038269},
+		largeP: []float64{1.7339, 0.93202, -0.12745, -0.010368},
+	},
+	2: {
+		critical: [3][4]float64{
+			{-3.89644, -10.9519, -33.527, 0},
+			{-3.33613, -6.1101, -6.823, 0},
+			{-3.04445, -4.2412, -2.720, 0},
+		},
+		tauMax: 0.92, tauMin: -18.86, tauStar: -2.62,
+		smallP: []float64{2.92, 1.5012, 0.039796},
+		largeP: []float64{2.1945, 0.64695, -0.29198, -0.042377},
+	},
+}
+
+// pValue approximate p-value of the statistic
+func (m mackinnonTable) pValue(statistic float64) float64 {
+	switch {
+	case statistic > m.tauMax:
+		return 1
+	case statistic < m.tauMin:
+		return 0
+	}
+
+	coef := m.largeP
+	if statistic <= m.tauStar {
+		coef = m.smallP
+	}
+
+	// polynomial in statistic, coef[0] is the constant term
+	z, power := 0.0, 1.0
+	for _, c := range coef {
+		z += c * power
+		power *= statistic
+	}
+	return distuv.UnitNormal.CDF(z)
+}
+
+// ADFTest augmented Dickey-Fuller test for a unit root, small PValue means the series is stationary
+//
+// Regression: Δy[t] = α + γ*y[t-1] + Σ β[i]*Δy[t-i], i = 1..lags, statistic is γ / se(γ).
+// Negative lags choose 12 * (n / 100)^(1/4) by Schwert rule.
+func ADFTest(series []float64, lags int) (ADFResult, error) {
+	return unitRootTest(series, lags, mackinnon[1])
+}
+
+// unitRootTest runs ADF regression and evaluates the statistic by the table
+func unitRootTest(series []float64, lags int, table mackinnonTable) (ADFResult, error) {
+	n := len(series)
+	if lags < 0 {
+		lags = int(12 * math.Pow(float64(n)/100, 0.25))
+	}
+
+	nobs := n - 1 - lags
+	cols := 2 + lags
+	if nobs <= cols+1 {
+		return ADFResult{}, ErrInsufficientData
+	}
+
+	diff := make([]float64, n-1)
+	floats.SubTo(diff, series[1:], series[:n-1])
+
+	x := mat.NewDense(nobs, cols, nil)
+	y := make([]float64, nobs)
+	for row := 0; row < nobs; row++ {
+		t := row + lags // index of Δy[t] in diff
+		y[row] = diff[t]
+		x.Set(row, 0, 1)
+		x.Set(row, 1, series[t])
+		for i := 1; i <= lags; i++ {
+			x.Set(row, 1+i, diff[t-i])
+		}
+	}
+
+	fit, err := ols(x, y)
+	if err != nil {
+		return ADFResult{}, err
+	}
+
+	statistic := fit.coef[1] / fit.stdErr[1]
+	T := float64(nobs)
+	return ADFResult{
+		Statistic:  statistic,
+		PValue:     table.pValue(statistic),
+		Lags:       lags,
+		NObs:       nobs,
+		Critical1:  mackinnonCritical(T, table.critical[0]),
+		Critical5:  mackinnonCritical(T, table.critical[1]),
+		Critical10: mackinnonCritical(T, table.critical[2]),
+	}, nil
+}
+
+// HurstExponent by rescaled range analysis of returns:
+// about 0.5 for random walk, above 0.5 for trending and below 0.5 for mean reverting series
+func HurstExponent(returns []float64) (float64, error) {
+	const minChunk = 8
+
+	n := len(returns)
+	if n < 4*minChunk {
+		return 0, ErrInsufficientData
+	}
+
+	var logSize, logRS []float64
+	for size := minChunk; size <= n/2; size *= 2 {
+		total, count := 0.0, 0
+		for start := 0; start+size <= n; start += size {
+			chunk := returns[start : start+size]
+			mean, std := Mean(chunk), math.Sqrt(varianceStandard(chunk, Mean(chunk)))
+			if std == 0 {
+				continue
+			}
+
+			cumulative, lowest, highest := 0.0, 0.0, 0.0
+			for _, v := range chunk {
+				cumulative += v - mean
+				lowest = math.Min(lowest, cumulative)
+				highest = math.Max(highest, cumulative)
+			}
+			total += (highest - lowest) / std
+			count++
+		}
+		if count > 0 {
+			logSize = append(logSize, math.Log(float64(size)))
+			logRS = append(logRS, math.Log(total/float64(count)))
+		}
+	}
+	if len(logSize) < 2 {
+		return 0, ErrInsufficientData
+	}
+
+	_, slope := stat.LinearRegression(logSize, logRS, nil, false)
+	return slope, nil
+}
+
+// Autocorrelation returns autocorrelation of lags 0..maxLag, the first value is always 1
+//
+// Formula: acf[k] = Σ (x[t] - mean)(x[t+k] - mean) / Σ (x[t] - mean)^2
+func Autocorrelation(series []float64, maxLag int) ([]float64, error) {
+	if maxLag < 0 {
+		return nil, ErrInvalidPeriod
+	}
+	if len(series) <= maxLag {
+		return nil, ErrInsufficientData
+	}
+
+	mean := Mean(series)
+	denominator := 0.0
+	for _, v := range series {
+		denominator += (v - mean) * (v - mean)
+	}
+	if denominator == 0 {
+		return nil, ErrInsufficientData
+	}
+
+	acf := make([]float64, maxLag+1)
+	for k := range acf {
+		sum := 0.0
+		for t := 0; t+k < len(series); t++ {
+			sum += (series[t] - mean) * (series[t+k] - mean)
+		}
+		acf[k] = sum / denominator
+	}
+	return acf, nil
+}
+
+// PartialAutocorrelation returns partial autocorrelation of lags 0..maxLag by Durbin-Levinson recursion,
+// the first value is always 1
+func PartialAutocorrelation(series []float64, maxLag int) ([]float64, error) {
+	acf, err := Autocorrelation(series, maxLag)
+	if err != nil {
+		return nil, err
+	}
+
+	pacf := make([]float64, maxLag+1)
+	pacf[0] = 1
+
+	phi := make([]float64, maxLag+1) // coefficients of the AR model of the current order
+	prev := make([]float64, maxLag+1)
+	for k := 1; k <= maxLag; k++ {
+		numerator, denominator := acf[k], 1.0
+		for j := 1; j < k; j++ {
+			numerator -= prev[j] * acf[k-j]
+			denominator -= prev[j] * acf[j]
+		}
+
+		phi[k] = numerator / denominator
+		for j := 1; j < k; j++ {
+			phi[j] = prev[j] - phi[k]*prev[k-j]
+		}
+		copy(prev, phi)
+		pacf[k] = phi[k]
+	}
+	return pacf, nil
+}
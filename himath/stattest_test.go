@@ -0,0 +1,114 @@
+package himath
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+)
+
+// randomSeries returns n values of AR(1) process x[t] = phi*x[t-1] + noise with the fixed seed
+func randomSeries(n int, phi float64) []float64 {
+	rnd := rand.New(rand.NewSource(42))
+	series := make([]float64, n)
+	for i := 1; i < n; i++ {
+		series[i] = phi*series[i-1] + rnd.NormFloat64()
+	}
+	return series
+}
+
+func TestTTest(t *testing.T) {
+	got, err := TTest([]float64{1, 2, 3, 4, 5}, 2)
+	assert.NoError(t, err)
+	assert.InDelta(t, 1.4142135623730951, got.Statistic, 1e-12)
+	assert.InDelta(t, 0.23019964107675203, got.PValue, 1e-9)
+	assert.Equal(t, 4.0, got.DF)
+
+	_, err = TTest([]float64{1}, 0)
+	assert.ErrorIs(t, err, ErrInsufficientData)
+}
+
+func TestWelchTTest(t *testing.T) {
+	got, err := WelchTTest([]float64{1, 2, 3, 4, 5}, []float64{2, 4, 6, 8, 10})
+	assert.NoError(t, err)
+	assert.InDelta(t, -1.8973665961010275, got.Statistic, 1e-12)
+	assert.InDelta(t, 5.882352941176471, got.DF, 1e-12)
+	assert.InDelta(t, 0.10753119493032583, got.PValue, 1e-9)
+
+	_, err = WelchTTest([]float64{1, 2}, []float64{1})
+	assert.ErrorIs(t, err, ErrInsufficientData)
+}
+
+func TestJarqueBera(t *testing.T) {
+	got, err := JarqueBera([]float64{1, 2, 3, 4, 10})
+	assert.NoError(t, err)
+	assert.InDelta(t, 1.0893633333333337, got.Statistic, 1e-12)
+	assert.InDelta(t, 0.5800263956901163, got.PValue, 1e-12)
+
+	_, err = JarqueBera([]float64{1, 1, 1})
+	assert.ErrorIs(t, err, ErrInsufficientData)
+}
+
+func TestSkewnessKurtosis(t *testing.T) {
+	symmetric := []float64{1, 2, 3, 4, 5}
+	assert.InDelta(t, 0, Skewness(symmetric), 1e-12)
+	assert.Greater(t, Skewness([]float64{1, 2, 3, 4, 10}), 0.0)
+	assert.Less(t, Kurtosis(symmetric), 0.0)
+}
+
+func TestADFTest(t *testing.T) {
+	stationary, err := ADFTest(randomSeries(500, 0.3), 1)
+	assert.NoError(t, err)
+	assert.True(t, stationary.Stationary())
+	assert.Less(t, stationary.PValue, 0.01)
+	assert.Equal(t, 498, stationary.NObs)
+	assert.InDelta(t, -2.8674, stationary.Critical5, 1e-3)
+	assert.Less(t, stationary.Critical1, stationary.Critical5)
+	assert.Less(t, stationary.Critical5, stationary.Critical10)
+
+	randomWalk, err := ADFTest(randomSeries(500, 1), -1)
+	assert.NoError(t, err)
+	assert.False(t, randomWalk.Stationary())
+	assert.Greater(t, randomWalk.PValue, 0.05)
+	assert.Equal(t, 17, randomWalk.Lags)
+
+	_, err = ADFTest([]float64{1, 2, 3}, 1)
+	assert.ErrorIs(t, err, ErrInsufficientData)
+}
+
+func TestMackinnonPValue(t *testing.T) {
+	assert.InDelta(t, 0.05, mackinnon[1].pValue(-2.8621), 0.005)
+	assert.Equal(t, 1.0, mackinnon[1].pValue(3))
+	assert.Equal(t, 0.0, mackinnon[1].pValue(-20))
+}
+
+func TestHurstExponent(t *testing.T) {
+	noise, err := HurstExponent(randomSeries(2048, 0))
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.5, noise, 0.1)
+
+	persistent, err := HurstExponent(randomSeries(2048, 0.9))
+	assert.NoError(t, err)
+	assert.Greater(t, persistent, 0.7)
+
+	antiPersistent, err := HurstExponent(randomSeries(2048, -0.8))
+	assert.NoError(t, err)
+	assert.Less(t, antiPersistent, noise-0.05)
+
+	_, err = HurstExponent(make([]float64, 10))
+	assert.ErrorIs(t, err, ErrInsufficientData)
+}
+
+func TestAutocorrelation(t *testing.T) {
+	acf, err := Autocorrelation([]float64{1, 2, 3, 4, 5}, 2)
+	assert.NoError(t, err)
+	assertSeries(t, []float64{1, 0.4, -0.1}, acf)
+
+	pacf, err := PartialAutocorrelation([]float64{1, 2, 3, 4, 5}, 2)
+	assert.NoError(t, err)
+	assertSeries(t, []float64{1, 0.4, -0.26 / 0.84}, pacf)
+
+	_, err = Autocorrelation([]float64{1, 2}, 2)
+	assert.ErrorIs(t, err, ErrInsufficientData)
+	_, err = Autocorrelation([]float64{1, 2}, -1)
+	assert.ErrorIs(t, err, ErrInvalidPeriod)
+}
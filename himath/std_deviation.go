@@ -7,6 +7,7 @@ func CalculateStandardDeviation(values []float64) float64 {
 }
 
 // Mean вычисляет среднее арифметическое последовательности чисел.
+// Для пустого среза и для среза с NaN возвращает NaN.
 func Mean(floatSlice []float64) float64 {
 	mean := 0.0
 	for _, v := range floatSlice {
@@ -17,14 +18,13 @@ func Mean(floatSlice []float64) float64 {
 }
 
 // StandardDeviation вычисляет стандартное отклонение последовательности чисел.
+// Для пустого среза, а также если срез или mean содержат NaN, возвращает NaN.
 func StandardDeviation(floatSlice []float64, mean float64) float64 {
-	res := math.Sqrt(varianceStandard(floatSlice, mean)) // Использование корня квадратного из дисперсии
-
-	if math.IsNaN(res) {
-		res = 0 // Возвращает 0, если результат является NaN
+	if len(floatSlice) == 0 || math.IsNaN(mean) {
+		return math.NaN() // Отклонение пустой последовательности не определено
 	}
 
-	return res
+	return math.Sqrt(varianceStandard(floatSlice, mean)) // Использование корня квадратного из дисперсии
 }
 
 // varianceStandard вычисляет дисперсию последовательности чисел по стандартной формуле.
@@ -0,0 +1,287 @@
+package himath
+
+import (
+	"github.com/AlexanderKolesnkov/golang-utils-stuff/consts"
+	"math"
+	"sort"
+)
+
+// VolumeProfile volume traded per price bin of equal size between Low and High
+type VolumeProfile struct {
+	Low     float64
+	High    float64
+	BinSize float64
+	Volumes []float64
+}
+
+// NewVolumeProfile distributes volume of every trade or candle into bins by its price, e.g. close or typical price.
+// Trades with NaN price are skipped.
+func NewVolumeProfile(bins int, price, volume []float64) (*VolumeProfile, error) {
+	if bins <= 0 {
+		return nil, ErrInvalidPeriod
+	}
+	if !sameLength(price, volume) {
+		return nil, ErrLengthMismatch
+	}
+	if len(price) == 0 {
+		return nil, ErrInsufficientData
+	}
+
+	p, err := newVolumeProfile(bins, price, price)
+	if err != nil {
+		return nil, err
+	}
+	for i := range price {
+		if math.IsNaN(price[i]) {
+			continue
+		}
+		p.Volumes[p.bin(price[i])] += volume[i]
+	}
+	return p, nil
+}
+
+// NewCandleVolumeProfile spreads volume of every candle evenly over its range from low to high.
+// Candles with NaN high or low are skipped.
+func NewCandleVolumeProfile(bins int, high, low, volume []float64) (*VolumeProfile, error) {
+	if bins <= 0 {
+		return nil, ErrInvalidPeriod
+	}
+	if !sameLength(high, low, volume) {
+		return nil, ErrLengthMismatch
+	}
+	if len(high) == 0 {
+		return nil, ErrInsufficientData
+	}
+
+	p, err := newVolumeProfile(bins, high, low)
+	if err != nil {
+		return nil, err
+	}
+	for i := range high {
+		if math.IsNaN(high[i]) || math.IsNaN(low[i]) {
+			continue
+		}
+		from, to := p.bin(low[i]), p.bin(high[i])
+		if from == to || high[i] == low[i] {
+			p.Volumes[from] += volume[i]
+			continue
+		}
+		density := volume[i] / (high[i] - low[i])
+		for b := from; b <= to; b++ {
+			binLow := p.Low + float64(b)*p.BinSize
+			overlap := math.Min(high[i], binLow+p.BinSize) - math.Max(low[i], binLow)
+			p.Volumes[b] += density * math.Max(overlap, 0)
+		}
+	}
+	return p, nil
+}
+
+// newVolumeProfile returns empty profile over the range of prices, ErrInsufficientData when all of them are NaN
+func newVolumeProfile(bins int, high, low []float64) (*VolumeProfile, error) {
+	p := &VolumeProfile{
+		Low:     Series(withoutNaN(low)).Min(),
+		High:    Series(withoutNaN(high)).Max(),
+		Volumes: make([]float64, bins),
+	}
+	if math.IsNaN(p.Low) || math.IsNaN(p.High) {
+		return nil, ErrInsufficientData
+	}
+	p.BinSize = (p.High - p.Low) / float64(bins)
+	return p, nil
+}
+
+// bin returns index of the bin of the price, High belongs to the last bin
+func (p *VolumeProfile) bin(price float64) int {
+	if p.BinSize == 0 {
+		return 0
+	}
+	i := int((price - p.Low) / p.BinSize)
+	return max(0, min(i, len(p.Volumes)-1))
+}
+
+// BinPrice returns the middle price of the bin
+func (p *VolumeProfile) BinPrice(i int) float64 {
+	return p.Low + (float64(i)+0.5)*p.BinSize
+}
+
+// PointOfControl returns the middle price and the index of the bin with the largest volume,
+// the lowest one of equal bins
+func (p *VolumeProfile) PointOfControl() (float64, int) {
+	poc := 0
+	for i, v := range p.Volumes {
+		if v > p.Volumes[poc] {
+			poc = i
+		}
+	}
+	return p.BinPrice(poc), poc
+}
+
+// ValueArea returns price range around the point of control holding the fraction of the volume, 0.7 as usual.
+// The area grows from the point of control to the neighbouring bin with more volume.
+func (p *VolumeProfile) ValueArea(fraction float64) (low, high float64, err error) {
+	if !(fraction > 0 && fraction <= 1) {
+		return 0, 0, ErrInvalidParameter
+	}
+
+	total := 0.0
+	for _, v := range p.Volumes {
+		total += v
+	}
+	_, poc := p.PointOfControl()
+	from, to := poc, poc
+	volume := p.Volumes[poc]
+	for volume < fraction*total && (from > 0 || to < len(p.Volumes)-1) {
+		below, above := math.Inf(-1), math.Inf(-1)
+		if from > 0 {
+			below = p.Volumes[from-1]
+		}
+		if to < len(p.Volumes)-1 {
+			above = p.Volumes[to+1]
+		}
+		if above >= below {
+			to++
+			volume += above
+		} else {
+			from--
+			volume += below
+		}
+	}
+	return p.Low + float64(from)*p.BinSize, p.Low + float64(to+1)*p.BinSize, nil
+}
+
+// AnchorIndex returns index of the first time at or after the anchor, times are sorted unix ms of the candles
+func AnchorIndex(times []int64, anchor int64) (int, error) {
+	i := sort.Search(len(times), func(i int) bool { return times[i] >= anchor })
+	if i == len(times) {
+		return 0, ErrInsufficientData
+	}
+	return i, nil
+}
+
+// AnchoredVwap volume weighted average price accumulated from the anchor index with bands
+// of multiplier volume weighted standard deviations, e.g. anchored at a session open or a swing low
+//
+// Formula: vwap = sum(price * volume) / sum(volume), std = sqrt(sum(price^2 * volume) / sum(volume) - vwap^2)
+//
+// Leading gap: anchor, NaN while the accumulated volume is zero
+func AnchoredVwap(anchor int, multiplier float64, price, volume []float64) (vwap, upper, lower []float64, err error) {
+	if !sameLength(price, volume) {
+		return nil, nil, nil, ErrLengthMismatch
+	}
+	if anchor < 0 || anchor >= len(price) {
+		return nil, nil, nil, ErrInvalidPeriod
+	}
+	if !(multiplier >= 0) {
+		return nil, nil, nil, ErrInvalidParameter
+	}
+
+	n := len(price)
+	vwap, upper, lower = nanSeries(n), nanSeries(n), nanSeries(n)
+	var pv, p2v, v float64
+	for i := anchor; i < n; i++ {
+		pv += price[i] * volume[i]
+		p2v += price[i] * price[i] * volume[i]
+		v += volume[i]
+		if v == 0 {
+			continue
+		}
+
+		vwap[i] = pv / v
+		// rounding can make the variance slightly negative for a flat price
+		std := math.Sqrt(math.Max(p2v/v-vwap[i]*vwap[i], 0))
+		upper[i] = vwap[i] + multiplier*std
+		lower[i] = vwap[i] - multiplier*std
+	}
+	return vwap, upper, lower, nil
+}
+
+// BookLevel price level of the order book
+type BookLevel struct {
+	Price float64
+	Qty   float64
+}
+
+// bookSide returns qty and qty weighted price of the first depth levels, depth 0 takes all levels.
+// Price is NaN when the levels have no qty.
+func bookSide(levels []BookLevel, depth int) (qty, price float64) {
+	if depth > 0 && depth < len(levels) {
+		levels = levels[:depth]
+	}
+	notional := 0.0
+	for _, level := range levels {
+		qty += level.Qty
+		notional += level.Price * level.Qty
+	}
+	if qty == 0 {
+		return 0, math.NaN()
+	}
+	return qty, notional / qty
+}
+
+// OrderBookImbalance returns imbalance of qty of the first depth levels in [-1, 1]:
+// positive when bids outweigh asks, depth 0 takes all levels
+//
+// Formula: (bidQty - askQty) / (bidQty + askQty)
+func OrderBookImbalance(bids, asks []BookLevel, depth int) (float64, error) {
+	if depth < 0 {
+		return 0, ErrInvalidPeriod
+	}
+	if len(bids) == 0 || len(asks) == 0 {
+		return 0, ErrInsufficientData
+	}
+
+	bidQty, _ := bookSide(bids, depth)
+	askQty, _ := bookSide(asks, depth)
+	if bidQty+askQty == 0 {
+		return math.NaN(), nil
+	}
+	return (bidQty - askQty) / (bidQty + askQty), nil
+}
+
+// DepthWeightedMid returns mid price leaning to the side with less qty, like the microprice of the best levels.
+// Prices of the sides are qty weighted over the first depth levels, depth 0 takes all levels.
+// When one side has no qty its price is undefined and the price of the other side is returned,
+// NaN when both sides have no qty.
+//
+// Formula: (bidPrice * askQty + askPrice * bidQty) / (bidQty + askQty)
+func DepthWeightedMid(bids, asks []BookLevel, depth int) (float64, error) {
+	if depth < 0 {
+		return 0, ErrInvalidPeriod
+	}
+	if len(bids) == 0 || len(asks) == 0 {
+		return 0, ErrInsufficientData
+	}
+
+	bidQty, bidPrice := bookSide(bids, depth)
+	askQty, askPrice := bookSide(asks, depth)
+	switch {
+	case bidQty == 0:
+		return askPrice, nil
+	case askQty == 0:
+		return bidPrice, nil
+	}
+	return (bidPrice*askQty + askPrice*bidQty) / (bidQty + askQty), nil
+}
+
+// CumulativeVolumeDelta running sum of volume of trades by the aggressor side:
+// consts.Buy adds the volume and consts.Sell subtracts it
+func CumulativeVolumeDelta(sides []string, volume []float64) ([]float64, error) {
+	if len(sides) != len(volume) {
+		return nil, ErrLengthMismatch
+	}
+
+	cvd := make([]float64, len(volume))
+	total := 0.0
+	for i, side := range sides {
+		switch side {
+		case consts.Buy:
+			total += volume[i]
+		case consts.Sell:
+			total -= volume[i]
+		default:
+			return nil, ErrInvalidSide
+		}
+		cvd[i] = total
+	}
+	return cvd, nil
+}
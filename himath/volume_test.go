@@ -0,0 +1,128 @@
+package himath
+
+import (
+	"github.com/AlexanderKolesnkov/golang-utils-stuff/consts"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestVolumeProfile(t *testing.T) {
+	p, err := NewVolumeProfile(4, []float64{1, 2, 3, 4, 5, nan}, []float64{1, 2, 3, 4, 5, 100})
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, p.Low)
+	assert.Equal(t, 5.0, p.High)
+	assert.Equal(t, []float64{1, 2, 3, 9}, p.Volumes, "high belongs to the last bin, NaN price is skipped")
+
+	price, index := p.PointOfControl()
+	assert.Equal(t, 4.5, price)
+	assert.Equal(t, 3, index)
+
+	low, high, err := p.ValueArea(0.7)
+	assert.NoError(t, err)
+	assert.Equal(t, 3.0, low)
+	assert.Equal(t, 5.0, high)
+
+	low, high, err = p.ValueArea(1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, low)
+	assert.Equal(t, 5.0, high)
+
+	_, _, err = p.ValueArea(0)
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+
+	_, err = NewVolumeProfile(0, []float64{1}, []float64{1})
+	assert.ErrorIs(t, err, ErrInvalidPeriod)
+	_, err = NewVolumeProfile(2, []float64{1}, nil)
+	assert.ErrorIs(t, err, ErrLengthMismatch)
+	_, err = NewVolumeProfile(2, []float64{nan}, []float64{1})
+	assert.ErrorIs(t, err, ErrInsufficientData)
+
+	flat, err := NewVolumeProfile(3, []float64{7, 7}, []float64{1, 2})
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{3, 0, 0}, flat.Volumes)
+}
+
+func TestCandleVolumeProfile(t *testing.T) {
+	p, err := NewCandleVolumeProfile(2, []float64{4, 1}, []float64{0, 1}, []float64{8, 2})
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{6, 4}, p.Volumes)
+
+	_, err = NewCandleVolumeProfile(2, []float64{4}, []float64{0, 1}, []float64{8})
+	assert.ErrorIs(t, err, ErrLengthMismatch)
+}
+
+func TestAnchoredVwap(t *testing.T) {
+	times := []int64{1000, 2000, 3000, 4000}
+	anchor, err := AnchorIndex(times, 1500)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, anchor)
+	_, err = AnchorIndex(times, 5000)
+	assert.ErrorIs(t, err, ErrInsufficientData)
+
+	vwap, upper, lower, err := AnchoredVwap(anchor, 1, []float64{10, 20, 30, 40}, []float64{1, 1, 1, 1})
+	assert.NoError(t, err)
+	assertSeries(t, []float64{nan, 20, 25, 30}, vwap)
+	assertSeries(t, []float64{nan, 20, 30, 30 + math.Sqrt(200.0/3)}, upper)
+	assertSeries(t, []float64{nan, 20, 20, 30 - math.Sqrt(200.0/3)}, lower)
+
+	zero, _, _, err := AnchoredVwap(0, 2, []float64{10, 20}, []float64{0, 1})
+	assert.NoError(t, err)
+	assertSeries(t, []float64{nan, 20}, zero)
+
+	_, _, _, err = AnchoredVwap(2, 1, []float64{10, 20}, []float64{1, 1})
+	assert.ErrorIs(t, err, ErrInvalidPeriod)
+	_, _, _, err = AnchoredVwap(0, 1, []float64{10, 20}, []float64{1})
+	assert.ErrorIs(t, err, ErrLengthMismatch)
+	_, _, _, err = AnchoredVwap(0, -1, []float64{10}, []float64{1})
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+}
+
+func TestOrderBook(t *testing.T) {
+	bids := []BookLevel{{Price: 100, Qty: 3}, {Price: 99, Qty: 5}}
+	asks := []BookLevel{{Price: 101, Qty: 1}, {Price: 102, Qty: 10}}
+
+	imbalance, err := OrderBookImbalance(bids, asks, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.5, imbalance)
+
+	imbalance, err = OrderBookImbalance(bids, asks, 0)
+	assert.NoError(t, err)
+	assert.InDelta(t, -3.0/19, imbalance, 1e-12)
+
+	mid, err := DepthWeightedMid(bids, asks, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 100.75, mid, "mid leans to the thin ask")
+
+	mid, err = DepthWeightedMid(bids, asks, 2)
+	assert.NoError(t, err)
+	bidPrice, askPrice := (300.0+495)/8, (101.0+1020)/11
+	assert.InDelta(t, (bidPrice*11+askPrice*8)/19, mid, 1e-12)
+
+	_, err = OrderBookImbalance(nil, asks, 1)
+	assert.ErrorIs(t, err, ErrInsufficientData)
+	_, err = DepthWeightedMid(bids, asks, -1)
+	assert.ErrorIs(t, err, ErrInvalidPeriod)
+
+	empty, err := DepthWeightedMid([]BookLevel{{Price: 100}}, []BookLevel{{Price: 101}}, 0)
+	assert.NoError(t, err)
+	assert.True(t, math.IsNaN(empty))
+
+	noBids, err := DepthWeightedMid([]BookLevel{{Price: 100}}, []BookLevel{{Price: 101, Qty: 2}, {Price: 102, Qty: 2}}, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 101.5, noBids, "price of the side with qty")
+	noAsks, err := DepthWeightedMid([]BookLevel{{Price: 100, Qty: 3}}, []BookLevel{{Price: 101}}, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 100.0, noAsks)
+}
+
+func TestCumulativeVolumeDelta(t *testing.T) {
+	cvd, err := CumulativeVolumeDelta([]string{consts.Buy, consts.Sell, consts.Sell, consts.Buy}, []float64{5, 2, 4, 1})
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{5, 3, -1, 0}, cvd)
+
+	_, err = CumulativeVolumeDelta([]string{"buy"}, []float64{1})
+	assert.ErrorIs(t, err, ErrInvalidSide)
+	_, err = CumulativeVolumeDelta([]string{consts.Buy}, nil)
+	assert.ErrorIs(t, err, ErrLengthMismatch)
+}
@@ -0,0 +1,44 @@
+package himath
+
+// window is a fixed size ring buffer over the last values of a stream.
+type window struct {
+	values []float64
+	pos    int
+	count  int
+}
+
+func newWindow(size int) *window {
+	return &window{values: make([]float64, size)}
+}
+
+// push adds the value and returns the evicted one, evicted is false until the window is full.
+func (w *window) push(value float64) (old float64, evicted bool) {
+	if w.count == len(w.values) {
+		old, evicted = w.values[w.pos], true
+	} else {
+		w.count++
+	}
+
+	w.values[w.pos] = value
+	w.pos = (w.pos + 1) % len(w.values)
+
+	return old, evicted
+}
+
+func (w *window) full() bool {
+	return w.count == len(w.values)
+}
+
+// minMax returns the lowest and the highest value in the window.
+func (w *window) minMax() (float64, float64) {
+	lowest, highest := w.values[0], w.values[0]
+	for _, v := range w.values[1:w.count] {
+		if v < lowest {
+			lowest = v
+		}
+		if v > highest {
+			highest = v
+		}
+	}
+	return lowest, highest
+}
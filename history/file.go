@@ -0,0 +1,69 @@
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"github.com/AlexanderKolesnkov/golang-utils-stuff/backtest"
+	"io"
+	"os"
+)
+
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// file decompressed content of an opened file
+type file struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (f *file) Close() error {
+	var first error
+	for i := len(f.closers) - 1; i >= 0; i-- {
+		if err := f.closers[i].Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// Open opens the file at path, gzip compressed content is detected by its magic bytes and decompressed
+func Open(path string) (io.ReadCloser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	buffered := bufio.NewReader(f)
+	magic, _ := buffered.Peek(len(gzipMagic))
+	if !bytes.Equal(magic, gzipMagic) {
+		return &file{Reader: buffered, closers: []io.Closer{f}}, nil
+	}
+
+	decompressed, err := gzip.NewReader(buffered)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &file{Reader: decompressed, closers: []io.Closer{f, decompressed}}, nil
+}
+
+// ReadCandlesFile reads candles from the plain or gzip compressed file
+func ReadCandlesFile(path string, options Options) ([]backtest.Candle, error) {
+	f, err := Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadCandles(f, options)
+}
+
+// ReadTableFile reads table from the plain or gzip compressed file
+func ReadTableFile(path string, options Options, columns ...string) (*Table, error) {
+	f, err := Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadTable(f, options, columns...)
+}
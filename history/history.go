@@ -0,0 +1,372 @@
+// Package history reads candles and indicator series from CSV files like the ones written by utils.WriteCsv
+// and the export package, plain or gzip compressed.
+package history
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/AlexanderKolesnkov/golang-utils-stuff/backtest"
+	"github.com/AlexanderKolesnkov/golang-utils-stuff/consts"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrMissingColumn = errors.New("missing column")
+	ErrNotMonotonic  = errors.New("time is not increasing")
+	ErrEmptyFile     = errors.New("empty file")
+)
+
+// ParseError malformed row of the file
+type ParseError struct {
+	Line   int
+	Column string
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	if e.Column == "" {
+		return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+	}
+	return fmt.Sprintf("line %d, column %s: %v", e.Line, e.Column, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Columns names of candle columns in the header, matched case-insensitively
+type Columns struct {
+	Time   string
+	Open   string
+	High   string
+	Low    string
+	Close  string
+	Volume string // optional, volume is 0 when the file has no such column
+}
+
+// DefaultColumns header of candle files: Time, Open, High, Low, Close, Volume
+var DefaultColumns = Columns{Time: "Time", Open: "Open", High: "High", Low: "Low", Close: "Close", Volume: "Volume"}
+
+// Options of the readers, the zero value reads comma separated files with a header,
+// times in consts.TimeLayout in UTC and requires increasing time
+type Options struct {
+	// Comma field delimiter, ',' by default
+	Comma rune
+	// Columns names of candle columns, DefaultColumns by default. ReadTable uses only Time.
+	Columns Columns
+	// NoHeader files have no header, columns follow in the order of DefaultColumns up to the number of fields,
+	// so OHLC files without volume work. Files of two fields are read as consts.SingleIndicatorHeader.
+	NoHeader bool
+	// TimeLayout of the time column, consts.TimeLayout by default
+	TimeLayout string
+	// UnixMilli time column holds Unix milliseconds instead of formatted time
+	UnixMilli bool
+	// Location of formatted times without zone, UTC by default
+	Location *time.Location
+	// AllowUnsorted skips the check that every time is after the previous one
+	AllowUnsorted bool
+}
+
+func (o Options) withDefaults() Options {
+	if o.Comma == 0 {
+		o.Comma = ','
+	}
+	if o.Columns == (Columns{}) {
+		o.Columns = DefaultColumns
+	}
+	if o.TimeLayout == "" {
+		o.TimeLayout = consts.TimeLayout
+	}
+	if o.Location == nil {
+		o.Location = time.UTC
+	}
+	return o
+}
+
+// parser reads records and converts fields keeping track of line numbers
+type parser struct {
+	options Options
+	csv     *csv.Reader
+	header  []string
+	last    time.Time
+	rows    int
+	// pending first record of a file without header, it is read to count the fields
+	pending []string
+}
+
+// newParser reads the header, files without header get the names of DefaultColumns
+// limited to the fields of the first record
+func newParser(r io.Reader, options Options) (*parser, error) {
+	options = options.withDefaults()
+	p := &parser{options: options, csv: csv.NewReader(r)}
+	p.csv.Comma = options.Comma
+	p.csv.TrimLeadingSpace = true
+	p.csv.ReuseRecord = true
+
+	if options.NoHeader {
+		record, err := p.csv.Read()
+		if errors.Is(err, io.EOF) {
+			return nil, ErrEmptyFile
+		}
+		if err != nil {
+			return nil, err
+		}
+		p.pending = append([]string(nil), record...)
+		p.header = defaultHeader(options.Columns, len(record))
+		return p, nil
+	}
+
+	header, err := p.csv.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, ErrEmptyFile
+	}
+	if err != nil {
+		return nil, err
+	}
+	p.header = make([]string, len(header))
+	for i, name := range header {
+		// utf-8 BOM written by spreadsheets
+		p.header[i] = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+	}
+	return p, nil
+}
+
+// defaultHeader returns names of n fields of a file without header,
+// fields beyond the candle columns are named by their 1-based position
+func defaultHeader(c Columns, n int) []string {
+	names := []string{c.Time, c.Open, c.High, c.Low, c.Close, c.Volume}
+	if n == len(consts.SingleIndicatorHeader()) {
+		names = []string{c.Time, consts.SingleIndicatorHeader()[1]}
+	}
+
+	header := make([]string, n)
+	for i := range header {
+		if i < len(names) {
+			header[i] = names[i]
+		} else {
+			header[i] = strconv.Itoa(i + 1)
+		}
+	}
+	return header
+}
+
+// index returns position of the column in the header, -1 when it is missing
+func (p *parser) index(name string) int {
+	for i, column := range p.header {
+		if strings.EqualFold(column, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// require returns positions of the columns, ErrMissingColumn for the first missing one
+func (p *parser) require(names ...string) ([]int, error) {
+	indexes := make([]int, len(names))
+	for i, name := range names {
+		if indexes[i] = p.index(name); indexes[i] < 0 {
+			return nil, fmt.Errorf("%w: %s", ErrMissingColumn, name)
+		}
+	}
+	return indexes, nil
+}
+
+// next returns the next record, io.EOF at the end
+func (p *parser) next() ([]string, error) {
+	if p.pending != nil {
+		record := p.pending
+		p.pending = nil
+		p.rows++
+		return record, nil
+	}
+
+	record, err := p.csv.Read()
+	if err != nil {
+		return nil, err
+	}
+	p.rows++
+	return record, nil
+}
+
+// line returns line of the field of the last record
+func (p *parser) line(field int) int {
+	line, _ := p.csv.FieldPos(field)
+	return line
+}
+
+func (p *parser) fail(field int, err error) error {
+	return &ParseError{Line: p.line(field), Column: p.header[field], Err: err}
+}
+
+// value returns the trimmed field, ErrMissingColumn when the record is shorter
+func (p *parser) value(record []string, field int) (string, error) {
+	if field >= len(record) {
+		line, _ := p.csv.FieldPos(0)
+		return "", &ParseError{Line: line, Column: p.header[field], Err: ErrMissingColumn}
+	}
+	return strings.TrimSpace(record[field]), nil
+}
+
+// time parses the time field and checks that time increases
+func (p *parser) time(record []string, field int) (time.Time, error) {
+	var t time.Time
+	value, err := p.value(record, field)
+	if err != nil {
+		return t, err
+	}
+	if p.options.UnixMilli {
+		ms, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return t, p.fail(field, err)
+		}
+		t = time.UnixMilli(ms).UTC()
+	} else {
+		if t, err = time.ParseInLocation(p.options.TimeLayout, value, p.options.Location); err != nil {
+			return t, p.fail(field, err)
+		}
+	}
+
+	if !p.options.AllowUnsorted && p.rows > 1 && !t.After(p.last) {
+		return t, p.fail(field, fmt.Errorf("%w: %s after %s", ErrNotMonotonic, value, p.last.Format(p.options.TimeLayout)))
+	}
+	p.last = t
+	return t, nil
+}
+
+// float parses the value field, empty field is NaN
+func (p *parser) float(record []string, field int) (float64, error) {
+	value, err := p.value(record, field)
+	if err != nil {
+		return 0, err
+	}
+	if value == "" {
+		return math.NaN(), nil
+	}
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, p.fail(field, err)
+	}
+	return v, nil
+}
+
+// ReadCandles reads OHLCV candles
+func ReadCandles(r io.Reader, options Options) ([]backtest.Candle, error) {
+	p, err := newParser(r, options)
+	if err != nil {
+		return nil, err
+	}
+	c := p.options.Columns
+	indexes, err := p.require(c.Time, c.Open, c.High, c.Low, c.Close)
+	if err != nil {
+		return nil, err
+	}
+	volume := p.index(c.Volume)
+
+	var candles []backtest.Candle
+	for {
+		record, err := p.next()
+		if errors.Is(err, io.EOF) {
+			return candles, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var candle backtest.Candle
+		if candle.Time, err = p.time(record, indexes[0]); err != nil {
+			return nil, err
+		}
+		for i, dst := range []*float64{&candle.Open, &candle.High, &candle.Low, &candle.Close} {
+			if *dst, err = p.float(record, indexes[i+1]); err != nil {
+				return nil, err
+			}
+		}
+		if volume >= 0 && volume < len(record) {
+			if candle.Volume, err = p.float(record, volume); err != nil {
+				return nil, err
+			}
+		}
+		candles = append(candles, candle)
+	}
+}
+
+// Table time-indexed columns of values
+type Table struct {
+	Times   []time.Time
+	Columns []string
+	Values  [][]float64 // Values[i] is the column Columns[i]
+}
+
+// Column returns values of the column, matched case-insensitively, nil when it is missing
+func (t *Table) Column(name string) []float64 {
+	for i, column := range t.Columns {
+		if strings.EqualFold(column, name) {
+			return t.Values[i]
+		}
+	}
+	return nil
+}
+
+// ReadTable reads the time column and the value columns, all columns after the time when none are given.
+// Single-indicator files of consts.SingleIndicatorHeader give the "Value" column.
+func ReadTable(r io.Reader, options Options, columns ...string) (*Table, error) {
+	p, err := newParser(r, options)
+	if err != nil {
+		return nil, err
+	}
+	timeIndex, err := p.require(p.options.Columns.Time)
+	if err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		// the header is sized to the fields that exist, so only those become columns
+		for i, name := range p.header {
+			if i != timeIndex[0] {
+				columns = append(columns, name)
+			}
+		}
+	}
+	indexes, err := p.require(columns...)
+	if err != nil {
+		return nil, err
+	}
+
+	table := &Table{Columns: columns, Values: make([][]float64, len(columns))}
+	for {
+		record, err := p.next()
+		if errors.Is(err, io.EOF) {
+			return table, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		t, err := p.time(record, timeIndex[0])
+		if err != nil {
+			return nil, err
+		}
+		table.Times = append(table.Times, t)
+		for i, index := range indexes {
+			v, err := p.float(record, index)
+			if err != nil {
+				return nil, err
+			}
+			table.Values[i] = append(table.Values[i], v)
+		}
+	}
+}
+
+// CandleColumns splits candles into columns for batch indicators
+func CandleColumns(candles []backtest.Candle) (open, high, low, closing, volume []float64) {
+	n := len(candles)
+	open, high, low, closing, volume = make([]float64, n), make([]float64, n), make([]float64, n), make([]float64, n), make([]float64, n)
+	for i, c := range candles {
+		open[i], high[i], low[i], closing[i], volume[i] = c.Open, c.High, c.Low, c.Close, c.Volume
+	}
+	return open, high, low, closing, volume
+}
@@ -0,0 +1,217 @@
+package history
+
+import (
+	"bytes"
+	"compress/gzip"
+	"github.com/AlexanderKolesnkov/golang-utils-stuff/backtest"
+	"github.com/AlexanderKolesnkov/golang-utils-stuff/export"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const candlesCsv = "\ufeffTime,Open,High,Low,Close,Volume\n" +
+	"2024-01-01 00:00:00,100,102,99,101,10\n" +
+	"2024-01-01 00:01:00,101,103,100,102.5,12.5\n"
+
+var (
+	t0 = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	t1 = time.Date(2024, 1, 1, 0, 1, 0, 0, time.UTC)
+)
+
+func TestReadCandles(t *testing.T) {
+	candles, err := ReadCandles(strings.NewReader(candlesCsv), Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, []backtest.Candle{
+		{Time: t0, Open: 100, High: 102, Low: 99, Close: 101, Volume: 10},
+		{Time: t1, Open: 101, High: 103, Low: 100, Close: 102.5, Volume: 12.5},
+	}, candles)
+
+	_, high, _, closing, volume := CandleColumns(candles)
+	assert.Equal(t, []float64{102, 103}, high)
+	assert.Equal(t, []float64{101, 102.5}, closing)
+	assert.Equal(t, []float64{10, 12.5}, volume)
+}
+
+func TestReadCandlesOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		options Options
+	}{
+		{
+			name:    "custom columns in any order without volume",
+			input:   "close;TS;low;high;open\n101;1704067200000;99;102;100\n",
+			options: Options{Comma: ';', UnixMilli: true, Columns: Columns{Time: "ts", Open: "open", High: "high", Low: "low", Close: "close"}},
+		},
+		{
+			name:    "no header",
+			input:   "2024-01-01T03:00:00,100,102,99,101,0\n",
+			options: Options{NoHeader: true, TimeLayout: "2006-01-02T15:04:05", Location: time.FixedZone("MSK", 3*3600)},
+		},
+		{
+			name:    "no header without volume",
+			input:   "2024-01-01 00:00:00,100,102,99,101\n",
+			options: Options{NoHeader: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			candles, err := ReadCandles(strings.NewReader(tt.input), tt.options)
+			assert.NoError(t, err)
+			assert.Len(t, candles, 1)
+			assert.True(t, t0.Equal(candles[0].Time), candles[0].Time)
+			assert.Equal(t, backtest.Candle{Time: candles[0].Time, Open: 100, High: 102, Low: 99, Close: 101}, candles[0])
+		})
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		err    error
+		line   int
+		column string
+	}{
+		{name: "malformed value", input: candlesCsv + "2024-01-01 00:02:00,1,2,3,x,4\n", line: 4, column: "Close"},
+		{name: "malformed time", input: candlesCsv + "01.01.2024,1,2,3,4,5\n", line: 4, column: "Time"},
+		{name: "time not increasing", input: candlesCsv + "2024-01-01 00:01:00,1,2,3,4,5\n", err: ErrNotMonotonic, line: 4, column: "Time"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadCandles(strings.NewReader(tt.input), Options{})
+			var parseErr *ParseError
+			assert.ErrorAs(t, err, &parseErr)
+			assert.Equal(t, tt.line, parseErr.Line)
+			assert.Equal(t, tt.column, parseErr.Column)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+			}
+			assert.Contains(t, err.Error(), "line 4")
+		})
+	}
+
+	unsorted := candlesCsv + "2024-01-01 00:00:30,1,2,3,4,5\n"
+	candles, err := ReadCandles(strings.NewReader(unsorted), Options{AllowUnsorted: true})
+	assert.NoError(t, err)
+	assert.Len(t, candles, 3)
+
+	_, err = ReadCandles(strings.NewReader("Time,Open,High,Low\n"), Options{})
+	assert.ErrorIs(t, err, ErrMissingColumn)
+	_, err = ReadCandles(strings.NewReader(""), Options{})
+	assert.ErrorIs(t, err, ErrEmptyFile)
+	_, err = ReadCandles(strings.NewReader(candlesCsv+"2024-01-01 00:02:00,1\n"), Options{})
+	assert.Error(t, err, "wrong number of fields")
+
+	_, err = ReadCandles(strings.NewReader("2024-01-01 00:00:00,1.5\n"), Options{NoHeader: true})
+	assert.ErrorIs(t, err, ErrMissingColumn)
+	_, err = ReadCandles(strings.NewReader(""), Options{NoHeader: true})
+	assert.ErrorIs(t, err, ErrEmptyFile)
+	_, err = ReadCandles(strings.NewReader("2024-01-01 00:00:00,1,2,0.5,1.5\n2024-01-01 00:01:00,1,2\n"), Options{NoHeader: true})
+	assert.ErrorContains(t, err, "line 2")
+}
+
+func TestReadTable(t *testing.T) {
+	input := "Time,Value\n2024-01-01 00:00:00,1.5\n2024-01-01 00:01:00,\n"
+	table, err := ReadTable(strings.NewReader(input), Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Time{t0, t1}, table.Times)
+	assert.Equal(t, []string{"Value"}, table.Columns)
+	value := table.Column("value")
+	assert.Equal(t, 1.5, value[0])
+	assert.True(t, math.IsNaN(value[1]), "empty field is NaN")
+	assert.Nil(t, table.Column("missing"))
+
+	selected, err := ReadTable(strings.NewReader(candlesCsv), Options{}, "Close", "Open")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]float64{{101, 102.5}, {100, 101}}, selected.Values)
+
+	_, err = ReadTable(strings.NewReader(candlesCsv), Options{}, "Rsi")
+	assert.ErrorIs(t, err, ErrMissingColumn)
+}
+
+func TestReadTableNoHeader(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		columns []string
+		values  [][]float64
+	}{
+		{
+			name:    "single indicator",
+			input:   "2024-01-01 00:00:00,1.5\n2024-01-01 00:01:00,2.5\n",
+			columns: []string{"Value"},
+			values:  [][]float64{{1.5, 2.5}},
+		},
+		{
+			name:    "ohlc without volume",
+			input:   "2024-01-01 00:00:00,1,2,0.5,1.5\n",
+			columns: []string{"Open", "High", "Low", "Close"},
+			values:  [][]float64{{1}, {2}, {0.5}, {1.5}},
+		},
+		{
+			name:    "extra fields",
+			input:   "2024-01-01 00:00:00,1,2,0.5,1.5,10,7\n",
+			columns: []string{"Open", "High", "Low", "Close", "Volume", "7"},
+			values:  [][]float64{{1}, {2}, {0.5}, {1.5}, {10}, {7}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table, err := ReadTable(strings.NewReader(tt.input), Options{NoHeader: true})
+			assert.NoError(t, err)
+			assert.Equal(t, t0, table.Times[0])
+			assert.Equal(t, tt.columns, table.Columns)
+			assert.Equal(t, tt.values, table.Values)
+		})
+	}
+}
+
+func TestExportRoundTrip(t *testing.T) {
+	times := []time.Time{t0, t1}
+	sma := []float64{math.NaN(), 100.25}
+
+	var buf bytes.Buffer
+	w, err := export.NewCSVWriter(&buf, export.Options{Columns: []string{"Sma"}})
+	assert.NoError(t, err)
+	assert.NoError(t, export.WriteSeries(w, times, sma))
+
+	table, err := ReadTable(&buf, Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, times, table.Times)
+	assert.True(t, math.IsNaN(table.Column("Sma")[0]))
+	assert.Equal(t, 100.25, table.Column("Sma")[1])
+}
+
+func TestReadFiles(t *testing.T) {
+	dir := t.TempDir()
+	plain := filepath.Join(dir, "candles.csv")
+	assert.NoError(t, os.WriteFile(plain, []byte(candlesCsv), 0o644))
+
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	_, err := gz.Write([]byte(candlesCsv))
+	assert.NoError(t, err)
+	assert.NoError(t, gz.Close())
+	zipped := filepath.Join(dir, "candles.csv.gz")
+	assert.NoError(t, os.WriteFile(zipped, compressed.Bytes(), 0o644))
+
+	want, _ := ReadCandles(strings.NewReader(candlesCsv), Options{})
+	for _, path := range []string{plain, zipped} {
+		candles, err := ReadCandlesFile(path, Options{})
+		assert.NoError(t, err, path)
+		assert.Equal(t, want, candles, path)
+	}
+
+	table, err := ReadTableFile(zipped, Options{}, "Volume")
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{10, 12.5}, table.Values[0])
+
+	_, err = ReadCandlesFile(filepath.Join(dir, "missing.csv"), Options{})
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,104 @@
+// Package render builds messages for Telegram and other chat clients in Markdown, MarkdownV2 and HTML
+// with escaping of user values, aligned tables and trade notifications.
+package render
+
+import (
+	"html"
+	"strings"
+)
+
+// Mode markup of the message, as parse_mode of Telegram Bot API
+type Mode int
+
+const (
+	// Markdown legacy Telegram Markdown
+	Markdown Mode = iota
+	MarkdownV2
+	HTML
+)
+
+var (
+	markdownEscaper   = newEscaper("_*`[")
+	markdownV2Escaper = newEscaper("\\_*[]()~`>#+-=|{}.!")
+	codeV2Escaper     = newEscaper("\\`")
+)
+
+// newEscaper returns replacer putting backslash before every special character
+func newEscaper(special string) *strings.Replacer {
+	pairs := make([]string, 0, 2*len(special))
+	for _, c := range special {
+		pairs = append(pairs, string(c), "\\"+string(c))
+	}
+	return strings.NewReplacer(pairs...)
+}
+
+// EscapeMarkdown escapes characters of legacy Telegram Markdown: _ * ` [
+func EscapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+// EscapeMarkdownV2 escapes all characters reserved by Telegram MarkdownV2, e.g. BTC_USDT becomes BTC\_USDT
+func EscapeMarkdownV2(s string) string {
+	return markdownV2Escaper.Replace(s)
+}
+
+// EscapeHTML escapes <, >, &, ' and "
+func EscapeHTML(s string) string {
+	return html.EscapeString(s)
+}
+
+// Escape escapes plain text for the mode
+func Escape(mode Mode, s string) string {
+	switch mode {
+	case MarkdownV2:
+		return EscapeMarkdownV2(s)
+	case HTML:
+		return EscapeHTML(s)
+	}
+	return EscapeMarkdown(s)
+}
+
+// Bold returns escaped text in bold
+func Bold(mode Mode, s string) string {
+	switch mode {
+	case MarkdownV2:
+		return "*" + EscapeMarkdownV2(s) + "*"
+	case HTML:
+		return "<b>" + EscapeHTML(s) + "</b>"
+	}
+	return "*" + EscapeMarkdown(s) + "*"
+}
+
+// Italic returns escaped text in italic
+func Italic(mode Mode, s string) string {
+	switch mode {
+	case MarkdownV2:
+		return "_" + EscapeMarkdownV2(s) + "_"
+	case HTML:
+		return "<i>" + EscapeHTML(s) + "</i>"
+	}
+	return "_" + EscapeMarkdown(s) + "_"
+}
+
+// Code returns text as inline code, only characters special inside code are escaped.
+// Legacy Markdown has no escaping inside code, so backticks are removed.
+func Code(mode Mode, s string) string {
+	switch mode {
+	case MarkdownV2:
+		return "`" + codeV2Escaper.Replace(s) + "`"
+	case HTML:
+		return "<code>" + EscapeHTML(s) + "</code>"
+	}
+	return "`" + strings.ReplaceAll(s, "`", "") + "`"
+}
+
+// Pre returns text as preformatted block keeping spaces, e.g. for tables
+func Pre(mode Mode, s string) string {
+	switch mode {
+	case MarkdownV2:
+		return "```\n" + codeV2Escaper.Replace(s) + "\n```"
+	case HTML:
+		return "<pre>" + EscapeHTML(s) + "</pre>"
+	}
+	return "```\n" + strings.ReplaceAll(s, "`", "") + "\n```"
+}
@@ -0,0 +1,80 @@
+package render
+
+import (
+	"github.com/AlexanderKolesnkov/golang-utils-stuff/consts"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestEscape(t *testing.T) {
+	tests := []struct {
+		mode Mode
+		text string
+		want string
+	}{
+		{mode: Markdown, text: "BTC_USDT *hot* [1]", want: "BTC\\_USDT \\*hot\\* \\[1]"},
+		{mode: MarkdownV2, text: "BTC_USDT -1.5% (x10)!", want: "BTC\\_USDT \\-1\\.5% \\(x10\\)\\!"},
+		{mode: MarkdownV2, text: `a\b`, want: `a\\b`},
+		{mode: HTML, text: `<b>"A&B"</b>`, want: "&lt;b&gt;&#34;A&amp;B&#34;&lt;/b&gt;"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Escape(tt.mode, tt.text))
+	}
+}
+
+func TestMarkup(t *testing.T) {
+	assert.Equal(t, "*BTC\\_USDT*", Bold(Markdown, "BTC_USDT"))
+	assert.Equal(t, "_1\\.5_", Italic(MarkdownV2, "1.5"))
+	assert.Equal(t, "<b>a&lt;b</b>", Bold(HTML, "a<b"))
+	assert.Equal(t, "`BTC_USDT 1.5 \\``", Code(MarkdownV2, "BTC_USDT 1.5 `"))
+	assert.Equal(t, "`ab`", Code(Markdown, "a`b"))
+	assert.Equal(t, "<pre>a &amp; b</pre>", Pre(HTML, "a & b"))
+	assert.Equal(t, "```\nx_1.5\n```", Pre(MarkdownV2, "x_1.5"))
+}
+
+func TestTable(t *testing.T) {
+	table := NewTable("Symbol", "Price", "Change")
+	table.AddRow("BTC_USDT", "42,000.50", "+1.25%")
+	table.AddRow("ETH", "2,250.00", "-0.50%")
+	table.AddValues("SOL", 98.7654)
+
+	want := "" +
+		"Symbol        Price  Change\n" +
+		"--------  ---------  ------\n" +
+		"BTC_USDT  42,000.50  +1.25%\n" +
+		"ETH        2,250.00  -0.50%\n" +
+		"SOL           98.77"
+	assert.Equal(t, want, table.String())
+	assert.Equal(t, "<pre>"+want+"</pre>", table.Render(HTML))
+
+	empty := NewTable("A", "B")
+	assert.Equal(t, "A  B\n-  -", empty.String())
+}
+
+func TestTradeNotifications(t *testing.T) {
+	entry := TradeEntry{Symbol: "BTC_USDT", Side: consts.Buy, Price: 42000, Qty: 0.01, Leverage: 10, StopLoss: 41000, TakeProfit: 44100}
+	assert.Equal(t, ""+
+		"*Buy BTC\\_USDT x10*\n"+
+		"Entry: 42,000\n"+
+		"Qty: 0\\.01\n"+
+		"Stop\\-loss: 41,000 \\(\\-23\\.81%\\)\n"+
+		"Take\\-profit: 44,100 \\(\\+50\\.00%\\)",
+		NewRenderer(MarkdownV2).Entry(entry))
+
+	short := TradeEntry{Symbol: "ETHUSDT", Side: consts.Sell, Price: 2000, Qty: 1.5, Leverage: 5, StopLoss: 2100}
+	assert.Equal(t, ""+
+		"<b>Sell ETHUSDT x5</b>\n"+
+		"Entry: 2,000\n"+
+		"Qty: 1.5\n"+
+		"Stop-loss: 2,100 (-25.00%)",
+		NewRenderer(HTML).Entry(short))
+
+	exit := TradeExit{Symbol: "BTC_USDT", Side: consts.Buy, EntryPrice: 42000, ExitPrice: 43000, Qty: 0.01, Leverage: 10, Pnl: 10}
+	assert.Equal(t, ""+
+		"<b>Closed Buy BTC_USDT x10</b>\n"+
+		"Entry: 42,000\n"+
+		"Exit: 43,000\n"+
+		"Qty: 0.01\n"+
+		"PnL: +10.00 (+23.81%)",
+		NewRenderer(HTML).Exit(exit))
+}
@@ -0,0 +1,103 @@
+package render
+
+import (
+	"github.com/AlexanderKolesnkov/golang-utils-stuff/formatters"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Table plain text table with columns padded to the widest cell.
+// Numeric columns are aligned to the right, so numbers of equal precision line up by the decimal separator.
+type Table struct {
+	headers []string
+	rows    [][]string
+	// Format of values added by AddValues
+	Format formatters.Format
+}
+
+// NewTable returns table with the headers, values are formatted with two decimals in LocaleEN
+func NewTable(headers ...string) *Table {
+	return &Table{headers: headers, Format: formatters.Format{Locale: formatters.LocaleEN, Precision: 2}}
+}
+
+// AddRow appends row of cells, missing cells are empty and extra cells are dropped
+func (t *Table) AddRow(cells ...string) *Table {
+	row := make([]string, len(t.headers))
+	copy(row, cells)
+	t.rows = append(t.rows, row)
+	return t
+}
+
+// AddValues appends row of the label followed by values formatted with Table.Format
+func (t *Table) AddValues(label string, values ...float64) *Table {
+	cells := make([]string, 0, len(values)+1)
+	cells = append(cells, label)
+	for _, v := range values {
+		cells = append(cells, t.Format.Format(v))
+	}
+	return t.AddRow(cells...)
+}
+
+// numeric reports whether the cell looks like a formatted number: digits with signs, separators,
+// currency or percent and compact suffix
+func numeric(cell string) bool {
+	digits := false
+	for _, r := range cell {
+		switch {
+		case unicode.IsDigit(r):
+			digits = true
+		case strings.ContainsRune("+-.,' %$€₽KMBT∞", r):
+		default:
+			return false
+		}
+	}
+	return digits
+}
+
+// String returns the table as plain text, columns are separated by two spaces and the header is underlined
+func (t *Table) String() string {
+	widths := make([]int, len(t.headers))
+	right := make([]bool, len(t.headers))
+	for i, header := range t.headers {
+		widths[i] = utf8.RuneCountInString(header)
+		right[i] = len(t.rows) > 0
+		for _, row := range t.rows {
+			widths[i] = max(widths[i], utf8.RuneCountInString(row[i]))
+			if row[i] != "" && !numeric(row[i]) {
+				right[i] = false
+			}
+		}
+	}
+
+	var b strings.Builder
+	writeRow := func(cells []string) {
+		line := make([]string, len(cells))
+		for i, cell := range cells {
+			padding := strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell))
+			if right[i] {
+				line[i] = padding + cell
+			} else {
+				line[i] = cell + padding
+			}
+		}
+		b.WriteString(strings.TrimRight(strings.Join(line, "  "), " "))
+		b.WriteByte('\n')
+	}
+
+	writeRow(t.headers)
+	underline := make([]string, len(widths))
+	for i, width := range widths {
+		underline[i] = strings.Repeat("-", width)
+	}
+	writeRow(underline)
+	for _, row := range t.rows {
+		writeRow(row)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// Render returns the table as preformatted block of the mode
+func (t *Table) Render(mode Mode) string {
+	return Pre(mode, t.String())
+}
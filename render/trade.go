@@ -0,0 +1,100 @@
+package render
+
+import (
+	"fmt"
+	"github.com/AlexanderKolesnkov/golang-utils-stuff/formatters"
+	"github.com/AlexanderKolesnkov/golang-utils-stuff/himath"
+	"strings"
+)
+
+// TradeEntry opened position, zero StopLoss or TakeProfit is not shown
+type TradeEntry struct {
+	Symbol     string
+	Side       string // consts.Buy or consts.Sell
+	Price      float64
+	Qty        float64
+	Leverage   float64
+	StopLoss   float64
+	TakeProfit float64
+}
+
+// TradeExit closed position, Pnl is realised pnl after fees
+type TradeExit struct {
+	Symbol     string
+	Side       string
+	EntryPrice float64
+	ExitPrice  float64
+	Qty        float64
+	Leverage   float64
+	Pnl        float64
+}
+
+// Renderer renders trade notifications in the mode with number formats of prices, qty, pnl and percents
+type Renderer struct {
+	Mode    Mode
+	Price   formatters.Format
+	Qty     formatters.Format
+	Pnl     formatters.Format
+	Percent formatters.Format // formats fractions, Style should be formatters.Percent
+}
+
+// NewRenderer returns renderer with LocaleEN formats: prices with 6 significant digits,
+// signed pnl with two decimals and signed percents with two decimals
+func NewRenderer(mode Mode) *Renderer {
+	return &Renderer{
+		Mode:    mode,
+		Price:   formatters.Format{Locale: formatters.LocaleEN, Significant: 6, TrimZeros: true},
+		Qty:     formatters.Format{Locale: formatters.LocaleEN, Significant: 6, TrimZeros: true},
+		Pnl:     formatters.Format{Locale: formatters.LocaleEN, Precision: 2, Sign: true},
+		Percent: formatters.Format{Locale: formatters.LocaleEN, Precision: 2, Style: formatters.Percent, Sign: true},
+	}
+}
+
+// percent formats return on margin given in percent like himath.CalcStopLossPcnt
+func (r *Renderer) percent(pcnt float64) string {
+	return r.Percent.Format(himath.ToFraction(pcnt))
+}
+
+func (r *Renderer) title(format string, args ...any) string {
+	return Bold(r.Mode, fmt.Sprintf(format, args...))
+}
+
+func (r *Renderer) line(label, value string) string {
+	return Escape(r.Mode, label+": "+value)
+}
+
+// Entry renders notification of the opened position with stop-loss and take-profit
+// in percent of the margin by himath.CalcStopLossPcnt
+//
+// Example: *Buy BTCUSDT x10* / Entry: 42,000 / Qty: 0.01 / Stop-loss: 41,000 (-23.81%)
+func (r *Renderer) Entry(entry TradeEntry) string {
+	lines := []string{
+		r.title("%s %s x%s", entry.Side, entry.Symbol, r.Qty.Format(entry.Leverage)),
+		r.line("Entry", r.Price.Format(entry.Price)),
+		r.line("Qty", r.Qty.Format(entry.Qty)),
+	}
+	if entry.StopLoss != 0 {
+		pcnt := himath.CalcStopLossPcnt(entry.StopLoss, entry.Price, entry.Leverage, entry.Side)
+		lines = append(lines, r.line("Stop-loss", fmt.Sprintf("%s (%s)", r.Price.Format(entry.StopLoss), r.percent(pcnt))))
+	}
+	if entry.TakeProfit != 0 {
+		pcnt := himath.CalcStopLossPcnt(entry.TakeProfit, entry.Price, entry.Leverage, entry.Side)
+		lines = append(lines, r.line("Take-profit", fmt.Sprintf("%s (%s)", r.Price.Format(entry.TakeProfit), r.percent(pcnt))))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Exit renders notification of the closed position with pnl in percent of the initial margin by himath.CalcPnlPcnt
+//
+// Example: *Closed Buy BTCUSDT x10* / Entry: 42,000 / Exit: 43,000 / PnL: +10.00 (+23.81%)
+func (r *Renderer) Exit(exit TradeExit) string {
+	margin := himath.CalcInitialMargin(exit.Qty, exit.EntryPrice, exit.Leverage)
+	pcnt := himath.CalcPnlPcnt(exit.Pnl, margin)
+	return strings.Join([]string{
+		r.title("Closed %s %s x%s", exit.Side, exit.Symbol, r.Qty.Format(exit.Leverage)),
+		r.line("Entry", r.Price.Format(exit.EntryPrice)),
+		r.line("Exit", r.Price.Format(exit.ExitPrice)),
+		r.line("Qty", r.Qty.Format(exit.Qty)),
+		r.line("PnL", fmt.Sprintf("%s (%s)", r.Pnl.Format(exit.Pnl), r.percent(pcnt))),
+	}, "\n")
+}